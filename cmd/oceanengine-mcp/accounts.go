@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// accountConfig is one entry of OCEANENGINE_ACCOUNTS_FILE: an authorizing
+// user, the token file written by "auth login" for them, and the advertisers
+// (including agency/majordomo child accounts) their token may act on.
+//
+//	[
+//	  {"user": "alice", "token_file": "/var/lib/oceanengine/alice.json", "advertiser_ids": [111, 222]},
+//	  {"user": "bob",   "token_file": "/var/lib/oceanengine/bob.json",   "advertiser_ids": [333]}
+//	]
+type accountConfig struct {
+	User          string  `json:"user"`
+	TokenFile     string  `json:"token_file"`
+	AdvertiserIDs []int64 `json:"advertiser_ids"`
+}
+
+// accountRegistry builds a token registry from the accounts file at path. Every
+// account refreshes through the shared developer app and persists its rotated
+// pair back to its own token file.
+func accountRegistry(path, baseURL string) (*oceanengine.TokenRegistry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read OCEANENGINE_ACCOUNTS_FILE: %w", err)
+	}
+	var accounts []accountConfig
+	if err := json.Unmarshal(raw, &accounts); err != nil {
+		return nil, fmt.Errorf("decode OCEANENGINE_ACCOUNTS_FILE: %w", err)
+	}
+	appID, secret, err := appCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	reg := oceanengine.NewTokenRegistry()
+	for _, a := range accounts {
+		if a.User == "" || a.TokenFile == "" {
+			return nil, fmt.Errorf("accounts file: every account needs a user and a token_file")
+		}
+		store := oceanengine.NewFileTokenStore(a.TokenFile)
+		stored, err := store.Load(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if stored == nil || stored.RefreshToken == "" {
+			return nil, fmt.Errorf("account %q: no token in %s; run: oceanengine-mcp auth login -token-file %s", a.User, a.TokenFile, a.TokenFile)
+		}
+		opts := []oceanengine.RefreshOption{oceanengine.WithTokenStore(store), oceanengine.WithOnSaveError(logSaveError)}
+		if baseURL != "" {
+			opts = append(opts, oceanengine.WithRefreshBaseURL(baseURL))
+		}
+		src := oceanengine.NewRefreshingTokenSource(appID, secret, stored.AccessToken, stored.RefreshToken,
+			time.Until(stored.AccessExpiry), opts...)
+		if err := reg.Register(oceanengine.Account{User: a.User, AdvertiserIDs: a.AdvertiserIDs, Tokens: src}); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// runAuth dispatches the "auth" subcommands.
+//
+//	oceanengine-mcp auth login [-listen addr] [-redirect-uri uri] [-token-file path]
+//
+// login performs the OAuth authorization-code flow for OCEANENGINE_APP_ID: it
+// prints the authorization URL, waits for the redirect on a local listener,
+// exchanges the auth_code for a token pair and writes it to the token file
+// (default OCEANENGINE_TOKEN_FILE), from which the server then starts.
+func runAuth(args []string) error {
+	if len(args) == 0 || args[0] != "login" {
+		return fmt.Errorf("usage: oceanengine-mcp auth login [flags]")
+	}
+	return authLogin(args[1:])
+}
+
+func authLogin(args []string) error {
+	fs := flag.NewFlagSet("auth login", flag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:8765", "address of the local OAuth callback listener")
+	redirectURI := fs.String("redirect-uri", "", "redirect URI registered for the app (default http://<listen>/callback)")
+	tokenFile := fs.String("token-file", os.Getenv("OCEANENGINE_TOKEN_FILE"), "file to write the token pair to")
+	timeout := fs.Duration("timeout", 10*time.Minute, "how long to wait for the authorization callback")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	appID, secret, err := appCredentials()
+	if err != nil {
+		return err
+	}
+	if *tokenFile == "" {
+		return fmt.Errorf("set OCEANENGINE_TOKEN_FILE or pass -token-file")
+	}
+	if *redirectURI == "" {
+		*redirectURI = "http://" + *listen + "/callback"
+	}
+	callback, err := url.Parse(*redirectURI)
+	if err != nil {
+		return fmt.Errorf("invalid -redirect-uri: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	state, err := randomState()
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	path := callback.Path
+	if path == "" {
+		path = "/"
+	}
+	codes := make(chan string, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != state || q.Get("auth_code") == "" {
+			http.Error(w, "invalid authorization callback", http.StatusBadRequest)
+			return
+		}
+		select {
+		case codes <- q.Get("auth_code"):
+			fmt.Fprintln(w, "Authorization received. You can close this window.")
+		default:
+			http.Error(w, "authorization already received", http.StatusConflict)
+		}
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	fmt.Fprintf(os.Stderr, "Open this URL in a browser and authorize the app:\n\n  %s\n\nWaiting for the redirect to %s ...\n",
+		oceanengine.AuthorizeURL(appID, *redirectURI, state), *redirectURI)
+
+	var code string
+	select {
+	case code = <-codes:
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("no authorization callback within %s", *timeout)
+		}
+		return ctx.Err()
+	}
+
+	opts := []oceanengine.RefreshOption{oceanengine.WithTokenStore(oceanengine.NewFileTokenStore(*tokenFile))}
+	if baseURL := os.Getenv("OCEANENGINE_BASE_URL"); baseURL != "" {
+		opts = append(opts, oceanengine.WithRefreshBaseURL(baseURL))
+	}
+	src := oceanengine.NewRefreshingTokenSource(appID, secret, "", "", 0, opts...)
+	advertiserIDs, err := src.ExchangeAuthCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Saved token pair to %s (authorized advertisers: %v).\n", *tokenFile, advertiserIDs)
+	return nil
+}
+
+// randomState returns an unguessable OAuth state value.
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+
+	"github.com/virgoC0der/go-mcp/internal/httpauth"
+	"github.com/virgoC0der/go-mcp/internal/mcpserver"
+)
+
+// clientsConfig is the content of OCEANENGINE_MCP_AUTH_FILE, which
+// authenticates callers of the HTTP transport and limits what each may do.
+// Callers present either a static API key or a JWT signed by a key in the
+// JWKS file as a bearer token; a JWT's "sub" claim must match a client ID.
+//
+//	{
+//	  "jwks_file": "/etc/oceanengine-mcp/jwks.json",
+//	  "jwt_issuer": "https://idp.example.com",
+//	  "jwt_audience": "oceanengine-mcp",
+//	  "clients": [
+//	    {"id": "reporting-bot", "api_key": "…", "advertiser_ids": [111], "tools": ["oceanengine_get_report"]},
+//	    {"id": "alice@example.com", "advertiser_ids": [111, 222]}
+//	  ]
+//	}
+//
+// An empty advertiser_ids or tools list leaves that dimension unrestricted.
+type clientsConfig struct {
+	JWKSFile    string         `json:"jwks_file"`
+	JWTIssuer   string         `json:"jwt_issuer"`
+	JWTAudience string         `json:"jwt_audience"`
+	Clients     []clientConfig `json:"clients"`
+}
+
+type clientConfig struct {
+	ID            string   `json:"id"`
+	APIKey        string   `json:"api_key"`
+	AdvertiserIDs []int64  `json:"advertiser_ids"`
+	Tools         []string `json:"tools"`
+}
+
+// httpClients builds the bearer-token verifier and the per-caller grants from
+// the auth file at path.
+func httpClients(path string) (auth.TokenVerifier, map[string]mcpserver.Grant, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read OCEANENGINE_MCP_AUTH_FILE: %w", err)
+	}
+	var cfg clientsConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("decode OCEANENGINE_MCP_AUTH_FILE: %w", err)
+	}
+
+	grants := map[string]mcpserver.Grant{}
+	keys := map[string]string{}
+	for _, c := range cfg.Clients {
+		if c.ID == "" {
+			return nil, nil, fmt.Errorf("auth file: every client needs an id")
+		}
+		if _, dup := grants[c.ID]; dup {
+			return nil, nil, fmt.Errorf("auth file: duplicate client %q", c.ID)
+		}
+		grants[c.ID] = mcpserver.Grant{AdvertiserIDs: c.AdvertiserIDs, Tools: c.Tools}
+		if c.APIKey != "" {
+			if _, dup := keys[c.APIKey]; dup {
+				return nil, nil, fmt.Errorf("auth file: client %q reuses another client's api_key", c.ID)
+			}
+			keys[c.APIKey] = c.ID
+		}
+	}
+
+	var verifiers []auth.TokenVerifier
+	if len(keys) > 0 {
+		verifiers = append(verifiers, httpauth.APIKeys(keys))
+	}
+	if cfg.JWKSFile != "" {
+		ks, err := httpauth.LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		verifiers = append(verifiers, httpauth.JWT(ks, httpauth.JWTOptions{
+			Issuer:   cfg.JWTIssuer,
+			Audience: cfg.JWTAudience,
+		}))
+	}
+	if len(verifiers) == 0 {
+		return nil, nil, fmt.Errorf("auth file: configure api_key for a client or a jwks_file")
+	}
+	return httpauth.Chain(verifiers...), grants, nil
+}
+
+// isLoopback reports whether the listen address only accepts local
+// connections.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
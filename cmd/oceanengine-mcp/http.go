@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// serveHTTP serves MCP over the streamable HTTP transport at /mcp, plus a
+// /healthz liveness probe, until ctx is done; it then stops accepting requests
+// and waits briefly for in-flight ones to finish.
+//
+// newServer is called once per MCP session, so sessions share no server state.
+// If verify is non-nil, /mcp requires a bearer token it accepts; the caller's
+// identity then reaches the tool handlers as the request's TokenInfo.
+func serveHTTP(ctx context.Context, addr string, newServer func() *mcp.Server, verify auth.TokenVerifier) error {
+	var handler http.Handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return newServer()
+	}, &mcp.StreamableHTTPOptions{SessionTimeout: 30 * time.Minute})
+	if verify != nil {
+		handler = auth.RequireBearerToken(verify, nil)(handler)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", handler)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	log.Printf("oceanengine-mcp: serving MCP on http://%s/mcp", addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	log.Printf("oceanengine-mcp: shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Streaming responses can outlive the grace period; cut them off.
+		_ = srv.Close()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+	}
+	return nil
+}
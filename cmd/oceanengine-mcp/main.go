@@ -1,7 +1,20 @@
 // Command oceanengine-mcp is a Model Context Protocol server for the Ocean
-// Engine (巨量引擎) advertising platform. It speaks MCP over stdio and exposes
-// tools for querying advertisers, campaigns, ads and performance reports — and,
-// when explicitly enabled, for mutating campaign status and budget.
+// Engine (巨量引擎) advertising platform. It speaks MCP over stdio (or streamable
+// HTTP) and exposes tools for querying advertisers, campaigns, ads and
+// performance reports — and, when explicitly enabled, for mutating campaign
+// status and budget.
+//
+// Usage:
+//
+//	oceanengine-mcp [-transport stdio|http] [-listen addr]   run the MCP server
+//	oceanengine-mcp auth login   obtain a first token pair via OAuth (see auth.go)
+//
+// With -transport=http the server listens on -listen (default 127.0.0.1:8080)
+// and serves MCP at /mcp and a liveness probe at /healthz; each MCP session
+// gets its own server instance. SIGINT/SIGTERM shut it down gracefully.
+// Callers authenticate with bearer tokens configured in OCEANENGINE_MCP_AUTH_FILE
+// (see clients.go), which also limits the advertisers and tools each may use;
+// without it the server only listens on loopback addresses.
 //
 // Configuration is via environment variables.
 //
@@ -16,21 +29,57 @@
 //	OCEANENGINE_REFRESH_TOKEN          OAuth refresh token
 //	OCEANENGINE_ACCESS_TOKEN           (optional) current access token
 //	OCEANENGINE_ACCESS_TOKEN_EXPIRES_IN (optional) remaining lifetime, seconds
+//	OCEANENGINE_TOKEN_FILE             (optional) file persisting the rotated pair
+//
+// When OCEANENGINE_TOKEN_FILE holds a token pair it takes precedence over the
+// token variables, which only seed the first run.
+//
+// Or, for agencies serving many authorizing users, point to an accounts file
+// (see accounts.go) mapping each user's token file to their advertisers:
+//
+//	OCEANENGINE_ACCOUNTS_FILE  JSON list of {user, token_file, advertiser_ids}
 //
 // Other options:
 //
 //	OCEANENGINE_BASE_URL       (optional) API host override
 //	OCEANENGINE_ENABLE_WRITES  (optional) set to "1"/"true" to register write tools
+//	OCEANENGINE_DRY_RUN        (optional) set to "1"/"true" to only preview writes;
+//	                           registers the write tools even without ENABLE_WRITES
+//	OCEANENGINE_ALLOWED_ADVERTISERS (optional) comma-separated advertiser IDs
+//	                           the tools may touch; others are refused
+//	OCEANENGINE_AUDIT_LOG      (optional) JSON Lines file recording every write call;
+//	                           also enables undoing recorded changes
+//	OCEANENGINE_QPS            (optional) client-side cap on requests per second
+//	OCEANENGINE_ADVERTISER_QPS (optional) cap per endpoint and advertiser
+//	OCEANENGINE_MCP_TRANSPORT  (optional) default for -transport
+//	OCEANENGINE_MCP_LISTEN     (optional) default for -listen
+//	OCEANENGINE_MCP_AUTH_FILE  (optional) HTTP callers, their credentials and grants
+//
+// Write guardrails (see mcpserver.WritePolicy):
+//
+//	OCEANENGINE_MAX_BUDGET             (optional) highest budget any object may be set to
+//	OCEANENGINE_MAX_BUDGET_CHANGE_PCT  (optional) largest budget change, % of the current one
+//	OCEANENGINE_MAX_BID                (optional) highest bid an ad or promotion may be set to
+//	OCEANENGINE_MAX_CAMPAIGNS_PER_CALL (optional) most objects one write call may touch
+//	OCEANENGINE_FORBID_DELETE          (optional) set to "1"/"true" to refuse deletes
+//	OCEANENGINE_CONFIRM_FALLBACK       (optional) "refuse" (default) or "allow" writes
+//	                                   when the client cannot ask the user to confirm
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/virgoC0der/go-mcp/internal/mcpserver"
@@ -41,6 +90,26 @@ import (
 var version = "dev"
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "auth" {
+		if err := runAuth(os.Args[2:]); err != nil {
+			log.Fatalf("oceanengine-mcp auth: %v", err)
+		}
+		return
+	}
+
+	transport := flag.String("transport", envOr("OCEANENGINE_MCP_TRANSPORT", "stdio"), "MCP transport: stdio or http")
+	listen := flag.String("listen", envOr("OCEANENGINE_MCP_LISTEN", "127.0.0.1:8080"), "listen address for -transport=http")
+	flag.Parse()
+
+	if err := run(*transport, *listen); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("oceanengine-mcp: %v", err)
+	}
+}
+
+// run serves MCP over transport until interrupted. It returns rather than
+// exiting on failure so that its deferred cleanup, such as closing the audit
+// log, always runs.
+func run(transport, listen string) error {
 	baseURL := os.Getenv("OCEANENGINE_BASE_URL")
 
 	var clientOpts []oceanengine.Option
@@ -48,22 +117,75 @@ func main() {
 		clientOpts = append(clientOpts, oceanengine.WithBaseURL(baseURL))
 	}
 
+	limit, err := rateLimit()
+	if err != nil {
+		return err
+	}
+	if limit.QPS > 0 || limit.AdvertiserQPS > 0 {
+		clientOpts = append(clientOpts, oceanengine.WithRateLimit(oceanengine.NewRateLimiter(limit)))
+	}
+
 	tokens, err := tokenProvider(baseURL)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	clientOpts = append(clientOpts, oceanengine.WithTokenProvider(tokens))
+	if appID, secret, err := appCredentials(); err == nil {
+		clientOpts = append(clientOpts, oceanengine.WithAppCredentials(appID, secret))
+	}
 
 	client := oceanengine.NewClient("", clientOpts...)
 
-	srv := mcpserver.New(client, mcpserver.Config{
-		Name:         "oceanengine-mcp",
-		Version:      version,
-		EnableWrites: envBool("OCEANENGINE_ENABLE_WRITES"),
-	})
+	allowed, err := advertiserAllowlist()
+	if err != nil {
+		return err
+	}
+	policy, err := writePolicy()
+	if err != nil {
+		return err
+	}
+	fallback, err := mcpserver.ParseConfirmFallback(envOr("OCEANENGINE_CONFIRM_FALLBACK", "refuse"))
+	if err != nil {
+		return fmt.Errorf("OCEANENGINE_CONFIRM_FALLBACK: %w", err)
+	}
+	var audit mcpserver.AuditSink
+	if path := os.Getenv("OCEANENGINE_AUDIT_LOG"); path != "" {
+		sink, err := mcpserver.NewFileAuditSink(path)
+		if err != nil {
+			return err
+		}
+		defer sink.Close()
+		audit = sink
+	}
+	cfg := mcpserver.Config{
+		Name:                 "oceanengine-mcp",
+		Version:              version,
+		EnableWrites:         envBool("OCEANENGINE_ENABLE_WRITES"),
+		DryRun:               envBool("OCEANENGINE_DRY_RUN"),
+		AllowedAdvertiserIDs: allowed,
+		Policy:               policy,
+		ConfirmFallback:      fallback,
+		Audit:                audit,
+	}
 
-	if err := srv.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
-		log.Fatalf("oceanengine-mcp: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch transport {
+	case "stdio":
+		return mcpserver.New(client, cfg).Run(ctx, &mcp.StdioTransport{})
+	case "http":
+		var verify auth.TokenVerifier
+		if path := os.Getenv("OCEANENGINE_MCP_AUTH_FILE"); path != "" {
+			if verify, cfg.Grants, err = httpClients(path); err != nil {
+				return err
+			}
+		} else if !isLoopback(listen) {
+			return fmt.Errorf("refusing to serve unauthenticated HTTP on %s: set OCEANENGINE_MCP_AUTH_FILE or listen on a loopback address", listen)
+		}
+		return serveHTTP(ctx, listen, func() *mcp.Server { return mcpserver.New(client, cfg) }, verify)
+	default:
+		return fmt.Errorf("unknown transport %q (want stdio or http)", transport)
 	}
 }
 
@@ -72,31 +194,122 @@ func envBool(key string) bool {
 	return b
 }
 
-// tokenProvider builds a token source from the environment, preferring the
-// self-refreshing OAuth source when app credentials and a refresh token are
-// present, and otherwise falling back to a static access token.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// advertiserAllowlist parses OCEANENGINE_ALLOWED_ADVERTISERS, a comma-separated
+// list of advertiser IDs.
+func advertiserAllowlist() ([]int64, error) {
+	var ids []int64
+	for f := range strings.SplitSeq(os.Getenv("OCEANENGINE_ALLOWED_ADVERTISERS"), ",") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(f, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("OCEANENGINE_ALLOWED_ADVERTISERS: %q is not an advertiser ID", f)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// writePolicy reads the write guardrails from the environment. Unset variables
+// impose no limit.
+func writePolicy() (mcpserver.WritePolicy, error) {
+	p := mcpserver.WritePolicy{ForbidDelete: envBool("OCEANENGINE_FORBID_DELETE")}
+	for key, dst := range map[string]*float64{
+		"OCEANENGINE_MAX_BUDGET":            &p.MaxBudget,
+		"OCEANENGINE_MAX_BUDGET_CHANGE_PCT": &p.MaxBudgetChangePercent,
+		"OCEANENGINE_MAX_BID":               &p.MaxBid,
+	} {
+		s := os.Getenv(key)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			return p, fmt.Errorf("%s must be a non-negative number", key)
+		}
+		*dst = v
+	}
+	if s := os.Getenv("OCEANENGINE_MAX_CAMPAIGNS_PER_CALL"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return p, fmt.Errorf("OCEANENGINE_MAX_CAMPAIGNS_PER_CALL must be a non-negative integer")
+		}
+		p.MaxCampaignsPerCall = n
+	}
+	return p, nil
+}
+
+// rateLimit reads the client-side QPS caps from the environment. Unset
+// variables leave the corresponding level unlimited.
+func rateLimit() (oceanengine.RateLimit, error) {
+	var l oceanengine.RateLimit
+	for key, dst := range map[string]*float64{
+		"OCEANENGINE_QPS":            &l.QPS,
+		"OCEANENGINE_ADVERTISER_QPS": &l.AdvertiserQPS,
+	} {
+		s := os.Getenv(key)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			return l, fmt.Errorf("%s must be a non-negative number", key)
+		}
+		*dst = v
+	}
+	return l, nil
+}
+
+// tokenProvider builds a token source from the environment: a per-advertiser
+// registry when an accounts file is configured, else the self-refreshing OAuth
+// source when app credentials and a refresh token are present, and otherwise a
+// static access token.
 func tokenProvider(baseURL string) (oceanengine.TokenProvider, error) {
+	if path := os.Getenv("OCEANENGINE_ACCOUNTS_FILE"); path != "" {
+		return accountRegistry(path, baseURL)
+	}
+
 	accessToken := os.Getenv("OCEANENGINE_ACCESS_TOKEN")
 	refreshToken := os.Getenv("OCEANENGINE_REFRESH_TOKEN")
-	appIDStr := os.Getenv("OCEANENGINE_APP_ID")
-	secret := os.Getenv("OCEANENGINE_APP_SECRET")
 
-	if refreshToken != "" && appIDStr != "" && secret != "" {
-		appID, err := strconv.ParseUint(appIDStr, 10, 64)
+	var expiresIn time.Duration
+	if s := os.Getenv("OCEANENGINE_ACCESS_TOKEN_EXPIRES_IN"); s != "" {
+		secs, err := strconv.ParseInt(s, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("OCEANENGINE_APP_ID must be numeric: %w", err)
+			return nil, fmt.Errorf("OCEANENGINE_ACCESS_TOKEN_EXPIRES_IN must be numeric seconds: %w", err)
 		}
-		var expiresIn time.Duration
-		if s := os.Getenv("OCEANENGINE_ACCESS_TOKEN_EXPIRES_IN"); s != "" {
-			secs, err := strconv.ParseInt(s, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("OCEANENGINE_ACCESS_TOKEN_EXPIRES_IN must be numeric seconds: %w", err)
-			}
-			expiresIn = time.Duration(secs) * time.Second
+		expiresIn = time.Duration(secs) * time.Second
+	}
+
+	var opts []oceanengine.RefreshOption
+	if baseURL != "" {
+		opts = append(opts, oceanengine.WithRefreshBaseURL(baseURL))
+	}
+	if path := os.Getenv("OCEANENGINE_TOKEN_FILE"); path != "" {
+		store := oceanengine.NewFileTokenStore(path)
+		stored, err := store.Load(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil && stored.RefreshToken != "" {
+			accessToken, refreshToken = stored.AccessToken, stored.RefreshToken
+			expiresIn = time.Until(stored.AccessExpiry)
 		}
-		var opts []oceanengine.RefreshOption
-		if baseURL != "" {
-			opts = append(opts, oceanengine.WithRefreshBaseURL(baseURL))
+		opts = append(opts, oceanengine.WithTokenStore(store), oceanengine.WithOnSaveError(logSaveError))
+	}
+
+	if refreshToken != "" && os.Getenv("OCEANENGINE_APP_ID") != "" && os.Getenv("OCEANENGINE_APP_SECRET") != "" {
+		appID, secret, err := appCredentials()
+		if err != nil {
+			return nil, err
 		}
 		return oceanengine.NewRefreshingTokenSource(appID, secret, accessToken, refreshToken, expiresIn, opts...), nil
 	}
@@ -106,3 +319,24 @@ func tokenProvider(baseURL string) (oceanengine.TokenProvider, error) {
 	}
 	return oceanengine.StaticToken(accessToken), nil
 }
+
+// appCredentials reads the developer app ID and secret from the environment.
+func appCredentials() (uint64, string, error) {
+	appIDStr := os.Getenv("OCEANENGINE_APP_ID")
+	secret := os.Getenv("OCEANENGINE_APP_SECRET")
+	if appIDStr == "" || secret == "" {
+		return 0, "", fmt.Errorf("OCEANENGINE_APP_ID and OCEANENGINE_APP_SECRET are required")
+	}
+	appID, err := strconv.ParseUint(appIDStr, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("OCEANENGINE_APP_ID must be numeric: %w", err)
+	}
+	return appID, secret, nil
+}
+
+// logSaveError reports a refreshed token pair that could not be saved. The
+// server keeps using it, but the token file now holds a rotated-out refresh
+// token.
+func logSaveError(err error) {
+	log.Printf("oceanengine-mcp: %v; the new token is in use but will be lost on restart", err)
+}
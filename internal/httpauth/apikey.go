@@ -0,0 +1,69 @@
+// Package httpauth verifies the bearer tokens presented to the MCP server's
+// HTTP transport. Its verifiers plug into the go-sdk's auth.RequireBearerToken
+// middleware and identify each caller through auth.TokenInfo.UserID, which
+// internal/mcpserver then maps to the caller's grant.
+package httpauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// apiKeyLifetime is the expiration reported for API keys. Keys do not expire,
+// but the go-sdk middleware requires one; it only has to outlast the request.
+const apiKeyLifetime = time.Hour
+
+// APIKeys returns a verifier for static API keys, given as a map from key to
+// the caller identity it authenticates.
+func APIKeys(keys map[string]string) auth.TokenVerifier {
+	// Compare fixed-size digests in constant time, so neither key contents
+	// nor key lengths leak through timing.
+	type entry struct {
+		sum      [sha256.Size]byte
+		identity string
+	}
+	entries := make([]entry, 0, len(keys))
+	for key, identity := range keys {
+		entries = append(entries, entry{sha256.Sum256([]byte(key)), identity})
+	}
+	return func(_ context.Context, token string, _ *http.Request) (*auth.TokenInfo, error) {
+		sum := sha256.Sum256([]byte(token))
+		identity := ""
+		for _, e := range entries {
+			if subtle.ConstantTimeCompare(sum[:], e.sum[:]) == 1 {
+				identity = e.identity
+			}
+		}
+		if identity == "" {
+			return nil, fmt.Errorf("%w: unknown API key", auth.ErrInvalidToken)
+		}
+		return &auth.TokenInfo{UserID: identity, Expiration: time.Now().Add(apiKeyLifetime)}, nil
+	}
+}
+
+// Chain returns a verifier that tries each verifier in turn and accepts the
+// first token one of them accepts. Errors other than auth.ErrInvalidToken stop
+// the chain.
+func Chain(verifiers ...auth.TokenVerifier) auth.TokenVerifier {
+	return func(ctx context.Context, token string, req *http.Request) (*auth.TokenInfo, error) {
+		err := fmt.Errorf("%w: no verifier configured", auth.ErrInvalidToken)
+		for _, v := range verifiers {
+			var info *auth.TokenInfo
+			info, err = v(ctx, token, req)
+			if err == nil {
+				return info, nil
+			}
+			if !errors.Is(err, auth.ErrInvalidToken) {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+}
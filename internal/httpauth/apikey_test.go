@@ -0,0 +1,40 @@
+package httpauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+func TestAPIKeys(t *testing.T) {
+	verify := APIKeys(map[string]string{"key-a": "alice", "key-b": "bob"})
+
+	info, err := verify(context.Background(), "key-b", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.UserID != "bob" || info.Expiration.IsZero() {
+		t.Fatalf("info = %+v, want bob with an expiration", info)
+	}
+
+	if _, err := verify(context.Background(), "key-c", nil); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("unknown key: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestChainFallsThrough(t *testing.T) {
+	verify := Chain(APIKeys(map[string]string{"k1": "one"}), APIKeys(map[string]string{"k2": "two"}))
+
+	info, err := verify(context.Background(), "k2", nil)
+	if err != nil || info.UserID != "two" {
+		t.Fatalf("verify(k2) = %+v, %v; want two", info, err)
+	}
+	if _, err := verify(context.Background(), "k3", nil); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("verify(k3): err = %v, want ErrInvalidToken", err)
+	}
+	if _, err := Chain()(context.Background(), "k1", nil); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("empty chain: err = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,320 @@
+package httpauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // register SHA-256 for crypto.Hash
+	_ "crypto/sha512" // register SHA-384/512 for crypto.Hash
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// KeySet is a set of public keys loaded from a JWKS document (RFC 7517).
+// RSA, EC (P-256, P-384) and Ed25519 keys are supported.
+type KeySet struct {
+	keys []jwk
+}
+
+type jwk struct {
+	kid string
+	alg string
+	pub crypto.PublicKey
+}
+
+type rawJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// LoadJWKS reads a JWKS file such as an identity provider publishes at its
+// jwks_uri. Keys not meant for signatures, or of unsupported types, are
+// skipped; a file without any usable key is an error.
+func LoadJWKS(path string) (*KeySet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("httpauth: read JWKS: %w", err)
+	}
+	return ParseJWKS(raw)
+}
+
+// ParseJWKS parses a JWKS document; see LoadJWKS.
+func ParseJWKS(raw []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []rawJWK `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("httpauth: decode JWKS: %w", err)
+	}
+	ks := &KeySet{}
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("httpauth: JWKS key %q: %w", k.Kid, err)
+		}
+		if pub != nil {
+			ks.keys = append(ks.keys, jwk{kid: k.Kid, alg: k.Alg, pub: pub})
+		}
+	}
+	if len(ks.keys) == 0 {
+		return nil, fmt.Errorf("httpauth: JWKS has no usable signing keys")
+	}
+	return ks, nil
+}
+
+// publicKey decodes k, returning nil for key types this package ignores.
+func (k rawJWK) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64Int(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64Int(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 {
+			return nil, errors.New("bad RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.New("bad EC coordinate length")
+		}
+		// Uncompressed SEC 1 point: 0x04 || X || Y.
+		point := append(append([]byte{4}, x...), y...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("bad Ed25519 key length")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+func b64Int(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// JWTOptions are the claim checks a JWT verifier applies on top of the
+// signature.
+type JWTOptions struct {
+	// Issuer, if set, must equal the "iss" claim.
+	Issuer string
+	// Audience, if set, must be one of the "aud" claim's values.
+	Audience string
+	// Leeway tolerates clock skew when checking "exp" and "nbf".
+	Leeway time.Duration
+}
+
+type claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	Scope     string   `json:"scope"`
+}
+
+// audience decodes the "aud" claim, which may be a string or a list.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// JWT returns a verifier for JWTs signed by one of the keys in ks, using
+// RS256/384/512, ES256/384 or EdDSA. The caller identity is the "sub" claim
+// and the scopes come from the space-separated "scope" claim. Tokens must
+// carry "exp".
+func JWT(ks *KeySet, opts JWTOptions) auth.TokenVerifier {
+	return func(_ context.Context, token string, _ *http.Request) (*auth.TokenInfo, error) {
+		c, err := ks.verify(token, opts, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", auth.ErrInvalidToken, err)
+		}
+		info := &auth.TokenInfo{
+			UserID:     c.Subject,
+			Expiration: time.Unix(c.ExpiresAt, 0).Add(opts.Leeway),
+		}
+		if c.Scope != "" {
+			info.Scopes = strings.Fields(c.Scope)
+		}
+		return info, nil
+	}
+}
+
+// verify checks the signature and claims of a compact JWS token.
+func (ks *KeySet) verify(token string, opts JWTOptions, now time.Time) (*claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed JWT")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		Typ string `json:"typ"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("JWT header: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("JWT signature: %w", err)
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+
+	verified := false
+	for _, k := range ks.keys {
+		if header.Kid != "" && k.kid != "" && k.kid != header.Kid {
+			continue
+		}
+		if k.alg != "" && k.alg != header.Alg {
+			continue
+		}
+		if verifySignature(k.pub, header.Alg, signed, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("signature does not verify (alg %q, kid %q)", header.Alg, header.Kid)
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("JWT claims: %w", err)
+	}
+	if c.ExpiresAt == 0 {
+		return nil, errors.New("JWT has no exp claim")
+	}
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(opts.Leeway)) {
+		return nil, errors.New("JWT expired")
+	}
+	if c.NotBefore != 0 && now.Add(opts.Leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return nil, errors.New("JWT not valid yet")
+	}
+	if opts.Issuer != "" && c.Issuer != opts.Issuer {
+		return nil, fmt.Errorf("JWT issuer %q not accepted", c.Issuer)
+	}
+	if opts.Audience != "" && !slices.Contains(c.Audience, opts.Audience) {
+		return nil, errors.New("JWT audience does not include this server")
+	}
+	if c.Subject == "" {
+		return nil, errors.New("JWT has no sub claim")
+	}
+	return &c, nil
+}
+
+// verifySignature reports whether sig is a valid alg signature of signed
+// under pub. Algorithms that do not match the key type never verify, so a
+// token cannot pick a weaker algorithm than its key was issued for.
+func verifySignature(pub crypto.PublicKey, alg string, signed, sig []byte) bool {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	case "EdDSA":
+		k, ok := pub.(ed25519.PublicKey)
+		return ok && ed25519.Verify(k, signed, sig)
+	default:
+		return false
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") && rsa.VerifyPKCS1v15(k, hash, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		// JWS encodes ECDSA signatures as fixed-width R || S.
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(sig) != 2*size || k.Curve.Params().BitSize != hash.Size()*8 {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	}
+	return false
+}
+
+func decodeSegment(seg string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
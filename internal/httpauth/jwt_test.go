@@ -0,0 +1,161 @@
+package httpauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+var b64 = base64.RawURLEncoding
+
+// sign builds a compact JWS over claims with the given signer.
+func sign(t *testing.T, alg, kid string, claims map[string]any, signer func([]byte) []byte) string {
+	t.Helper()
+	h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := b64.EncodeToString(h) + "." + b64.EncodeToString(c)
+	return signed + "." + b64.EncodeToString(signer([]byte(signed)))
+}
+
+func rsaSigner(t *testing.T, key *rsa.PrivateKey) func([]byte) []byte {
+	return func(msg []byte) []byte {
+		sum := sha256.Sum256(msg)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+}
+
+func keySet(t *testing.T, keys ...map[string]string) *KeySet {
+	t.Helper()
+	raw, _ := json.Marshal(map[string]any{"keys": keys})
+	ks, err := ParseJWKS(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":   "https://idp.example",
+		"sub":   "alice",
+		"aud":   []string{"oceanengine-mcp"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "read write",
+	}
+}
+
+func TestJWTRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keySet(t, map[string]string{
+		"kty": "RSA", "kid": "k1", "use": "sig",
+		"n": b64.EncodeToString(key.N.Bytes()),
+		"e": b64.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	})
+	verify := JWT(ks, JWTOptions{Issuer: "https://idp.example", Audience: "oceanengine-mcp"})
+
+	info, err := verify(context.Background(), sign(t, "RS256", "k1", validClaims(), rsaSigner(t, key)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.UserID != "alice" || len(info.Scopes) != 2 || info.Expiration.IsZero() {
+		t.Fatalf("info = %+v", info)
+	}
+
+	for name, mutate := range map[string]func(map[string]any){
+		"expired":      func(c map[string]any) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"no exp":       func(c map[string]any) { delete(c, "exp") },
+		"not before":   func(c map[string]any) { c["nbf"] = time.Now().Add(time.Hour).Unix() },
+		"wrong issuer": func(c map[string]any) { c["iss"] = "https://evil.example" },
+		"wrong aud":    func(c map[string]any) { c["aud"] = "someone-else" },
+		"no subject":   func(c map[string]any) { delete(c, "sub") },
+	} {
+		c := validClaims()
+		mutate(c)
+		if _, err := verify(context.Background(), sign(t, "RS256", "k1", c, rsaSigner(t, key)), nil); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", name, err)
+		}
+	}
+
+	// A token signed by another key, or tampered with, must not verify.
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, err := verify(context.Background(), sign(t, "RS256", "k1", validClaims(), rsaSigner(t, other)), nil); err == nil {
+		t.Error("token signed by an unknown key verified")
+	}
+	tok := sign(t, "RS256", "k1", validClaims(), rsaSigner(t, key))
+	parts := strings.Split(tok, ".")
+	forged, _ := json.Marshal(map[string]any{"sub": "mallory", "exp": time.Now().Add(time.Hour).Unix()})
+	parts[1] = b64.EncodeToString(forged)
+	if _, err := verify(context.Background(), strings.Join(parts, "."), nil); err == nil {
+		t.Error("tampered token verified")
+	}
+}
+
+func TestJWTES256AndEdDSA(t *testing.T) {
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, _ := ec.PublicKey.Bytes() // 0x04 || X || Y
+	ks := keySet(t,
+		map[string]string{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64.EncodeToString(pt[1:33]), "y": b64.EncodeToString(pt[33:])},
+		map[string]string{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": b64.EncodeToString(edPub)},
+	)
+	verify := JWT(ks, JWTOptions{})
+
+	esTok := sign(t, "ES256", "ec", validClaims(), func(msg []byte) []byte {
+		sum := sha256.Sum256(msg)
+		r, s, err := ecdsa.Sign(rand.Reader, ec, sum[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig
+	})
+	if _, err := verify(context.Background(), esTok, nil); err != nil {
+		t.Fatalf("ES256: %v", err)
+	}
+
+	edTok := sign(t, "EdDSA", "ed", validClaims(), func(msg []byte) []byte { return ed25519.Sign(edPriv, msg) })
+	if _, err := verify(context.Background(), edTok, nil); err != nil {
+		t.Fatalf("EdDSA: %v", err)
+	}
+
+	// alg "none" and algorithm/key mismatches are rejected.
+	none := sign(t, "none", "", validClaims(), func([]byte) []byte { return nil })
+	if _, err := verify(context.Background(), none, nil); err == nil {
+		t.Error(`alg "none" verified`)
+	}
+}
+
+func TestParseJWKSRejectsEmpty(t *testing.T) {
+	if _, err := ParseJWKS([]byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`)); err == nil {
+		t.Fatal("JWKS with only a symmetric key should be rejected")
+	}
+}
@@ -0,0 +1,111 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Grant is what one authenticated caller may do. An empty AdvertiserIDs or
+// Tools list leaves that dimension unrestricted.
+type Grant struct {
+	// AdvertiserIDs are the accounts the caller may reference.
+	AdvertiserIDs []int64
+	// Tools are the tool names the caller may call.
+	Tools []string
+}
+
+func (g Grant) allowsTool(name string) bool {
+	return len(g.Tools) == 0 || slices.Contains(g.Tools, name)
+}
+
+func (g Grant) allowsAdvertiser(id int64) bool {
+	return len(g.AdvertiserIDs) == 0 || slices.Contains(g.AdvertiserIDs, id)
+}
+
+// guard enforces Config.AllowedAdvertiserIDs and Config.Grants. Every tool
+// handler calls authorize before it touches the Ocean Engine client.
+type guard struct {
+	// allowed is the server-wide advertiser allowlist; empty allows all.
+	allowed []int64
+	// grants is nil when no per-caller ACL is configured (e.g. over stdio).
+	grants map[string]Grant
+}
+
+// grantFor returns the grant of the caller identified by the request's bearer
+// token, or an error if the caller is unknown.
+func (g *guard) grantFor(extra *mcp.RequestExtra) (Grant, error) {
+	if g.grants == nil {
+		return Grant{}, nil
+	}
+	if extra == nil || extra.TokenInfo == nil || extra.TokenInfo.UserID == "" {
+		return Grant{}, fmt.Errorf("access denied: the request carries no caller identity")
+	}
+	grant, ok := g.grants[extra.TokenInfo.UserID]
+	if !ok {
+		return Grant{}, fmt.Errorf("access denied: caller %q has no grant on this server", extra.TokenInfo.UserID)
+	}
+	return grant, nil
+}
+
+// authorize checks that the caller of req may call the tool and that every
+// one of advertiserIDs is on the allowlist and in the caller's grant. The
+// error for an advertiser does not say which list refused it, so callers
+// cannot probe for the accounts the server can reach.
+func (g *guard) authorize(req *mcp.CallToolRequest, advertiserIDs ...int64) error {
+	grant, err := g.grantFor(req.Extra)
+	if err != nil {
+		return err
+	}
+	if !grant.allowsTool(req.Params.Name) {
+		return fmt.Errorf("access denied: this caller may not use %s", req.Params.Name)
+	}
+	for _, id := range advertiserIDs {
+		if !g.inAllowlist(id) || !grant.allowsAdvertiser(id) {
+			return fmt.Errorf("access denied: advertiser %d is not permitted", id)
+		}
+	}
+	return nil
+}
+
+// visibleAdvertisers reports which advertisers the caller of req may see; it
+// is used to filter results that list accounts rather than take one.
+func (g *guard) visibleAdvertisers(req *mcp.CallToolRequest) func(int64) bool {
+	grant, err := g.grantFor(req.Extra)
+	if err != nil {
+		return func(int64) bool { return false }
+	}
+	return func(id int64) bool { return g.inAllowlist(id) && grant.allowsAdvertiser(id) }
+}
+
+// restricted reports whether some advertisers may be hidden from callers.
+func (g *guard) restricted() bool {
+	return len(g.allowed) > 0 || g.grants != nil
+}
+
+func (g *guard) inAllowlist(id int64) bool {
+	return len(g.allowed) == 0 || slices.Contains(g.allowed, id)
+}
+
+// filterToolList is receiving middleware that hides the tools a caller may
+// not use from tools/list. Calls are still checked by authorize.
+func (g *guard) filterToolList(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		res, err := next(ctx, method, req)
+		if err != nil || g.grants == nil {
+			return res, err
+		}
+		list, ok := res.(*mcp.ListToolsResult)
+		if !ok {
+			return res, err
+		}
+		grant, gerr := g.grantFor(req.GetExtra())
+		if gerr != nil {
+			return nil, gerr
+		}
+		list.Tools = slices.DeleteFunc(list.Tools, func(t *mcp.Tool) bool { return !grant.allowsTool(t.Name) })
+		return list, nil
+	}
+}
@@ -0,0 +1,163 @@
+package mcpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// bearerTransport adds a fixed bearer token to every request.
+type bearerTransport struct{ token string }
+
+func (b bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+b.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// connectHTTP serves cfg over streamable HTTP behind a bearer-token check
+// whose tokens are the caller identities themselves, and connects as token.
+func connectHTTP(t *testing.T, apiURL string, cfg Config, token string) *mcp.ClientSession {
+	t.Helper()
+	client := oceanengine.NewClient("tok", oceanengine.WithBaseURL(apiURL))
+	verify := func(_ context.Context, tok string, _ *http.Request) (*auth.TokenInfo, error) {
+		return &auth.TokenInfo{UserID: tok, Expiration: time.Now().Add(time.Hour)}, nil
+	}
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return New(client, cfg) }, nil)
+	ts := httptest.NewServer(auth.RequireBearerToken(verify, nil)(handler))
+	t.Cleanup(ts.Close)
+
+	c := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v0"}, nil)
+	cs, err := c.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:   ts.URL,
+		HTTPClient: &http.Client{Transport: bearerTransport{token}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { _ = cs.Close() })
+	return cs
+}
+
+func TestGrantsEnforced(t *testing.T) {
+	var apiCalls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		apiCalls.Add(1)
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"id":7}],"page_info":{"page":1,"total_page":1}}}`))
+	}))
+	defer ts.Close()
+
+	cfg := Config{EnableWrites: true, Grants: map[string]Grant{
+		"alice": {AdvertiserIDs: []int64{1}, Tools: []string{"oceanengine_list_campaigns", "oceanengine_get_report"}},
+	}}
+	cs := connectHTTP(t, ts.URL, cfg, "alice")
+
+	names := toolNames(t, cs)
+	if len(names) != 2 || !names["oceanengine_list_campaigns"] || !names["oceanengine_get_report"] {
+		t.Fatalf("tools/list = %v, want only alice's two tools", names)
+	}
+
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := call("oceanengine_list_campaigns", map[string]any{"advertiser_id": 1}); res.IsError {
+		t.Fatalf("granted call failed: %+v", res.Content)
+	}
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{"oceanengine_list_campaigns", map[string]any{"advertiser_id": 2}},
+		{"oceanengine_update_campaign_status", map[string]any{"advertiser_id": 1, "campaign_ids": []int64{7}, "opt_status": "disable"}},
+	} {
+		res := call(tc.tool, tc.args)
+		if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "access denied") {
+			t.Errorf("%s %v: want access denied, got %+v", tc.tool, tc.args, res.Content)
+		}
+	}
+	if n := apiCalls.Load(); n != 1 {
+		t.Fatalf("Ocean Engine calls = %d, want 1; denied calls must not reach the client", n)
+	}
+}
+
+func TestUnknownCallerDenied(t *testing.T) {
+	cfg := Config{Grants: map[string]Grant{"alice": {}}}
+	cs := connectHTTP(t, "http://unused", cfg, "mallory")
+
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_list_campaigns",
+		Arguments: map[string]any{"advertiser_id": 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "no grant") {
+		t.Fatalf("want a no-grant error, got %+v", res.Content)
+	}
+}
+
+func TestAdvertiserAllowlist(t *testing.T) {
+	var apiCalls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiCalls.Add(1)
+		if r.URL.Path == "/open_api/oauth2/advertiser/get/" {
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[
+				{"advertiser_id":1,"account_role":"ADVERTISER"},
+				{"advertiser_id":2,"account_role":"ADVERTISER"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[],"page_info":{"page":1,"total_page":1}}}`))
+	}))
+	defer ts.Close()
+
+	client := oceanengine.NewClient("tok", oceanengine.WithBaseURL(ts.URL), oceanengine.WithAppCredentials(1, "s"))
+	cs := connectClient(t, client, Config{EnableWrites: true, AllowedAdvertiserIDs: []int64{1}})
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{"oceanengine_get_advertiser_info", map[string]any{"advertiser_ids": []int64{1, 2}}},
+		{"oceanengine_list_ads", map[string]any{"advertiser_id": 2}},
+		{"oceanengine_update_campaign_budget", map[string]any{"advertiser_id": 2, "campaign_id": 9, "budget": 500}},
+	} {
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: tc.tool, Arguments: tc.args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "access denied: advertiser 2 is not permitted") {
+			t.Errorf("%s: want access denied, got %+v", tc.tool, res.Content)
+		}
+		if text := res.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "1") {
+			t.Errorf("%s: error %q reveals the allowlist", tc.tool, text)
+		}
+	}
+	if n := apiCalls.Load(); n != 0 {
+		t.Fatalf("Ocean Engine calls = %d, want 0", n)
+	}
+
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "oceanengine_list_authorized_advertisers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out listAuthorizedOutput
+	decodeStructured(t, res, &out)
+	if len(out.Advertisers) != 1 || out.Advertisers[0].AdvertiserID != 1 {
+		t.Fatalf("advertisers = %+v, want only the allowlisted one", out.Advertisers)
+	}
+}
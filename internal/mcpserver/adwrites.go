@@ -0,0 +1,207 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// ---------------------------------------------------------------------------
+// Ad (广告计划) write tools
+// ---------------------------------------------------------------------------
+
+type updateAdStatusInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	AdIDs        []int64 `json:"ad_ids" jsonschema:"ad IDs to update"`
+	OptStatus    string  `json:"opt_status" jsonschema:"one of: enable, disable, delete"`
+	DryRun       bool    `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+type adBudget struct {
+	AdID   int64   `json:"ad_id" jsonschema:"ad ID"`
+	Budget float64 `json:"budget" jsonschema:"new budget amount; the ad keeps its budget mode"`
+}
+
+type updateAdBudgetInput struct {
+	AdvertiserID int64      `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	Ads          []adBudget `json:"ads" jsonschema:"the ads to update, each with its new budget"`
+	DryRun       bool       `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+type adBid struct {
+	AdID int64   `json:"ad_id" jsonschema:"ad ID"`
+	Bid  float64 `json:"bid" jsonschema:"new bid; the target conversion bid (cpa_bid) for oCPM/oCPC ads"`
+}
+
+type updateAdBidInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	Ads          []adBid `json:"ads" jsonschema:"the ads to update, each with its new bid"`
+	DryRun       bool    `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+// AdChange is one ad's state before and after a write.
+type AdChange struct {
+	AdID       int64  `json:"ad_id"`
+	AdName     string `json:"ad_name"`
+	CampaignID int64  `json:"campaign_id"`
+	Before     State  `json:"before"`
+	After      State  `json:"after"`
+}
+
+func adStateOf(a oceanengine.Ad) State {
+	return State{Status: a.Status, OptStatus: a.OptStatus, Budget: a.Budget, BudgetMode: a.BudgetMode, Bid: adBidOf(a)}
+}
+
+// adBidOf returns the bid /2/ad/update/bid/ changes: the target conversion
+// bid for oCPM/oCPC ads, the plain bid otherwise.
+func adBidOf(a oceanengine.Ad) float64 {
+	if strings.Contains(a.Pricing, "OCP") && a.CpaBid > 0 {
+		return a.CpaBid
+	}
+	return a.Bid
+}
+
+// adOptStatusResult maps an opt_status argument to the ad status it leads to.
+var adOptStatusResult = map[string]string{
+	"enable":  "AD_STATUS_ENABLE",
+	"disable": "AD_STATUS_DISABLE",
+	"delete":  "AD_STATUS_DELETE",
+}
+
+func registerAdWriteTools(srv *mcp.Server, w *writeTools, mode string) {
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolAdStatus,
+		Description: mode + ": enable, disable or delete Ocean Engine (巨量引擎) ads (广告计划), several per call. This mutates the live account unless dry_run is set. The user is asked to confirm the change first. Returns each ad's status before and after.",
+		Annotations: w.writeTool("Update ad status", true, true),
+	}, audited(w, func(in updateAdStatusInput) int64 { return in.AdvertiserID }, w.updateAdStatus))
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolAdBudget,
+		Description: mode + ": set new budgets for Ocean Engine (巨量引擎) ads (广告计划), several per call. This mutates the live account unless dry_run is set. The user is asked to confirm the change first. Returns each ad's budget before and after.",
+		Annotations: w.writeTool("Update ad budget", false, true),
+	}, audited(w, func(in updateAdBudgetInput) int64 { return in.AdvertiserID }, w.updateAdBudget))
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolAdBid,
+		Description: mode + ": set new bids for Ocean Engine (巨量引擎) ads (广告计划), several per call; for oCPM/oCPC ads this is the target conversion bid. This mutates the live account unless dry_run is set. The user is asked to confirm the change first. Returns each ad's bid before and after.",
+		Annotations: w.writeTool("Update ad bid", false, true),
+	}, audited(w, func(in updateAdBidInput) int64 { return in.AdvertiserID }, w.updateAdBid))
+}
+
+func adChange(a oceanengine.Ad) AdChange {
+	return AdChange{AdID: a.ID, AdName: a.Name, CampaignID: a.CampaignID, Before: adStateOf(a), After: adStateOf(a)}
+}
+
+func (w *writeTools) updateAdStatus(ctx context.Context, req *mcp.CallToolRequest, in updateAdStatusInput, e *AuditEntry) (*writeOutput, error) {
+	after, ok := adOptStatusResult[in.OptStatus]
+	if !ok {
+		return nil, fmt.Errorf("opt_status must be one of enable, disable, delete")
+	}
+	if err := w.checkBatch(req, "ad", in.AdvertiserID, in.AdIDs); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkStatus("ads", in.OptStatus, len(in.AdIDs)); err != nil {
+		return nil, err
+	}
+	cur, err := currentAds(ctx, w.client, in.AdvertiserID, in.AdIDs)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]AdChange, len(in.AdIDs))
+	for i, id := range in.AdIDs {
+		changes[i] = adChange(cur[id])
+		changes[i].After.Status, changes[i].After.OptStatus = after, after
+	}
+	summary := statusSummary(in.AdvertiserID, in.OptStatus, "ad", adLabels(changes))
+	e.AdChanges = changes
+	return w.commit(ctx, req, e, &writeOutput{AdChanges: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdateAdStatus(ctx, in.AdvertiserID, in.AdIDs, in.OptStatus)
+	})
+}
+
+func (w *writeTools) updateAdBudget(ctx context.Context, req *mcp.CallToolRequest, in updateAdBudgetInput, e *AuditEntry) (*writeOutput, error) {
+	ids := make([]int64, len(in.Ads))
+	for i, a := range in.Ads {
+		if a.Budget <= 0 {
+			return nil, fmt.Errorf("budget for ad %d must be positive", a.AdID)
+		}
+		ids[i] = a.AdID
+	}
+	if err := w.checkBatch(req, "ad", in.AdvertiserID, ids); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkCount("ads", len(ids)); err != nil {
+		return nil, err
+	}
+	cur, err := currentAds(ctx, w.client, in.AdvertiserID, ids)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]AdChange, len(in.Ads))
+	budgets := make([]oceanengine.AdBudget, len(in.Ads))
+	for i, a := range in.Ads {
+		ad := cur[a.AdID]
+		mode := finiteBudgetMode(ad.BudgetMode)
+		if err := w.policy.checkBudget("ad "+strconv.FormatInt(a.AdID, 10), adStateOf(ad), a.Budget, mode); err != nil {
+			return nil, err
+		}
+		changes[i] = adChange(ad)
+		changes[i].After.Budget, changes[i].After.BudgetMode = a.Budget, mode
+		budgets[i] = oceanengine.AdBudget{AdID: a.AdID, Budget: a.Budget}
+	}
+	items := make([]string, len(changes))
+	for i, c := range changes {
+		items[i] = fromTo(adLabel(c), budgetString(c.Before), budgetString(c.After))
+	}
+	summary := valueSummary(in.AdvertiserID, "budget", "ad", items)
+	e.AdChanges = changes
+	return w.commit(ctx, req, e, &writeOutput{AdChanges: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdateAdBudget(ctx, in.AdvertiserID, budgets)
+	})
+}
+
+func (w *writeTools) updateAdBid(ctx context.Context, req *mcp.CallToolRequest, in updateAdBidInput, e *AuditEntry) (*writeOutput, error) {
+	ids := make([]int64, len(in.Ads))
+	for i, a := range in.Ads {
+		ids[i] = a.AdID
+	}
+	if err := w.checkBatch(req, "ad", in.AdvertiserID, ids); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkCount("ads", len(ids)); err != nil {
+		return nil, err
+	}
+	for _, a := range in.Ads {
+		if a.Bid <= 0 {
+			return nil, fmt.Errorf("bid for ad %d must be positive", a.AdID)
+		}
+		if err := w.policy.checkBid("ad "+strconv.FormatInt(a.AdID, 10), a.Bid); err != nil {
+			return nil, err
+		}
+	}
+	cur, err := currentAds(ctx, w.client, in.AdvertiserID, ids)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]AdChange, len(in.Ads))
+	bids := make([]oceanengine.AdBid, len(in.Ads))
+	for i, a := range in.Ads {
+		changes[i] = adChange(cur[a.AdID])
+		changes[i].After.Bid = a.Bid
+		bids[i] = oceanengine.AdBid{AdID: a.AdID, Bid: a.Bid}
+	}
+	items := make([]string, len(changes))
+	for i, c := range changes {
+		items[i] = fromTo(adLabel(c), bidString(c.Before), bidString(c.After))
+	}
+	summary := valueSummary(in.AdvertiserID, "bid", "ad", items)
+	e.AdChanges = changes
+	return w.commit(ctx, req, e, &writeOutput{AdChanges: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdateAdBid(ctx, in.AdvertiserID, bids)
+	})
+}
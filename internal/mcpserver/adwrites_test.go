@@ -0,0 +1,138 @@
+package mcpserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const testAds = `[
+	{"id":21,"name":"X","campaign_id":7,"status":"AD_STATUS_DELIVERY_OK","opt_status":"AD_STATUS_ENABLE","budget":200,"budget_mode":"BUDGET_MODE_DAY","pricing":"PRICING_OCPM","cpa_bid":12},
+	{"id":22,"name":"Y","campaign_id":7,"status":"AD_STATUS_DELIVERY_OK","opt_status":"AD_STATUS_ENABLE","budget":300,"budget_mode":"BUDGET_MODE_DAY","pricing":"PRICING_CPC","bid":0.8},
+	{"id":23,"name":"Z","campaign_id":7,"status":"AD_STATUS_DELIVERY_OK","opt_status":"AD_STATUS_ENABLE","budget_mode":"BUDGET_MODE_INFINITE","pricing":"PRICING_CPC","bid":0.5}]`
+
+// adAPI serves /2/ad/get/ with testAds and records the bodies sent to the ad
+// update endpoints, by path.
+func adAPI(t *testing.T, updates map[string]string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/open_api/2/ad/get/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":` + testAds + `,"page_info":{"page":1,"total_page":1}}}`))
+		case strings.HasPrefix(r.URL.Path, "/open_api/2/ad/update/"):
+			b, _ := io.ReadAll(r.Body)
+			updates[r.URL.Path] = string(b)
+			_, _ = w.Write([]byte(`{"code":0,"request_id":"req-ad","data":{}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func callWriteTool(t *testing.T, cs *mcp.ClientSession, name string, args map[string]any) (*mcp.CallToolResult, writeOutput) {
+	t.Helper()
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out writeOutput
+	if !res.IsError {
+		decodeStructured(t, res, &out)
+	}
+	return res, out
+}
+
+func TestAdWritesBatch(t *testing.T) {
+	updates := map[string]string{}
+	cs := connect(t, adAPI(t, updates).URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow})
+
+	res, out := callWriteTool(t, cs, toolAdStatus, map[string]any{"advertiser_id": 1, "ad_ids": []int64{21, 22}, "opt_status": "disable"})
+	if res.IsError || !out.OK || len(out.AdChanges) != 2 || out.AdChanges[1].After.Status != "AD_STATUS_DISABLE" {
+		t.Fatalf("status: %+v %+v", res.Content, out)
+	}
+	if got := updates["/open_api/2/ad/update/status/"]; got != `{"ad_ids":[21,22],"advertiser_id":1,"opt_status":"disable"}` {
+		t.Errorf("status body = %s", got)
+	}
+
+	res, out = callWriteTool(t, cs, toolAdBudget, map[string]any{"advertiser_id": 1, "ads": []map[string]any{{"ad_id": 21, "budget": 250}, {"ad_id": 22, "budget": 350}}})
+	if res.IsError || out.AdChanges[0].Before.Budget != 200 || out.AdChanges[0].After.Budget != 250 {
+		t.Fatalf("budget: %+v %+v", res.Content, out)
+	}
+	if got := updates["/open_api/2/ad/update/budget/"]; got != `{"advertiser_id":1,"data":[{"ad_id":21,"budget":250},{"ad_id":22,"budget":350}]}` {
+		t.Errorf("budget body = %s", got)
+	}
+
+	res, out = callWriteTool(t, cs, toolAdBid, map[string]any{"advertiser_id": 1, "ads": []map[string]any{{"ad_id": 21, "bid": 15}, {"ad_id": 22, "bid": 1}}})
+	if res.IsError {
+		t.Fatalf("bid: %+v", res.Content)
+	}
+	// The oCPM ad's bid is its target conversion bid.
+	if b := out.AdChanges[0]; b.Before.Bid != 12 || b.After.Bid != 15 {
+		t.Errorf("oCPM bid change = %+v", b)
+	}
+	if b := out.AdChanges[1]; b.Before.Bid != 0.8 || b.After.Bid != 1 {
+		t.Errorf("CPC bid change = %+v", b)
+	}
+}
+
+func TestAdWritesValidated(t *testing.T) {
+	updates := map[string]string{}
+	cs := connect(t, adAPI(t, updates).URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow,
+		Policy: WritePolicy{MaxBid: 10, ForbidDelete: true, MaxBudgetChangePercent: 50}})
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+		want string
+	}{
+		{toolAdStatus, map[string]any{"advertiser_id": 1, "ad_ids": []int64{21}, "opt_status": "pause"}, "opt_status must be"},
+		{toolAdStatus, map[string]any{"advertiser_id": 1, "ad_ids": []int64{21}, "opt_status": "delete"}, "deleting ads is disabled"},
+		{toolAdStatus, map[string]any{"advertiser_id": 1, "ad_ids": []int64{21, 21}, "opt_status": "disable"}, "listed more than once"},
+		{toolAdStatus, map[string]any{"advertiser_id": 1, "ad_ids": []int64{99}, "opt_status": "disable"}, "ad 99 not found"},
+		{toolAdBudget, map[string]any{"advertiser_id": 1, "ads": []map[string]any{{"ad_id": 21, "budget": 0}}}, "must be positive"},
+		{toolAdBudget, map[string]any{"advertiser_id": 1, "ads": []map[string]any{{"ad_id": 21, "budget": 500}}}, "changing ad 21's budget"},
+		{toolAdBid, map[string]any{"advertiser_id": 1, "ads": []map[string]any{{"ad_id": 22, "bid": 11}}}, "maximum bid of 10"},
+		{toolAdBid, map[string]any{"advertiser_id": 1, "ads": []map[string]any{}}, "at least one ad"},
+	} {
+		res, _ := callWriteTool(t, cs, tc.tool, tc.args)
+		if msg := errorText(res); !strings.Contains(msg, tc.want) {
+			t.Errorf("%s %v: error %q, want it to mention %q", tc.tool, tc.args, msg, tc.want)
+		}
+	}
+	if len(updates) != 0 {
+		t.Fatalf("refused writes reached Ocean Engine: %v", updates)
+	}
+}
+
+func TestAdBudgetCapsUnlimitedAd(t *testing.T) {
+	updates := map[string]string{}
+	cs := connect(t, adAPI(t, updates).URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow, Policy: WritePolicy{MaxBudget: 1000}})
+
+	// Giving an unlimited ad a budget within the maximum is allowed.
+	res, out := callWriteTool(t, cs, toolAdBudget, map[string]any{"advertiser_id": 1, "ads": []map[string]any{{"ad_id": 23, "budget": 500}}})
+	if res.IsError {
+		t.Fatalf("budget: %+v", res.Content)
+	}
+	if c := out.AdChanges[0]; c.Before.BudgetMode != budgetModeInfinite || c.After.Budget != 500 || c.After.BudgetMode != "BUDGET_MODE_DAY" {
+		t.Fatalf("change = %+v", c)
+	}
+}
+
+func TestAdBidAuthorizedBeforePolicy(t *testing.T) {
+	updates := map[string]string{}
+	cs := connect(t, adAPI(t, updates).URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow,
+		AllowedAdvertiserIDs: []int64{2}, Policy: WritePolicy{MaxBid: 10}})
+
+	// A caller outside the allowlist must not learn the server's policy.
+	res, _ := callWriteTool(t, cs, toolAdBid, map[string]any{"advertiser_id": 1, "ads": []map[string]any{{"ad_id": 22, "bid": 11}}})
+	if msg := errorText(res); !strings.Contains(msg, "access denied") {
+		t.Fatalf("error %q, want access denied", msg)
+	}
+}
@@ -0,0 +1,42 @@
+package mcpserver
+
+import "github.com/modelcontextprotocol/go-sdk/mcp"
+
+// Tool annotations tell clients which tools are safe to call without asking
+// the user. Every tool must carry one; TestToolAnnotations enforces it.
+
+// readTool annotates a tool that only reads from Ocean Engine.
+func readTool(title string) *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{
+		Title:           title,
+		ReadOnlyHint:    true,
+		DestructiveHint: boolPtr(false),
+		IdempotentHint:  true,
+		OpenWorldHint:   boolPtr(true),
+	}
+}
+
+// localReadTool annotates a tool that only reads this server's own state.
+func localReadTool(title string) *mcp.ToolAnnotations {
+	a := readTool(title)
+	a.OpenWorldHint = boolPtr(false)
+	return a
+}
+
+// writeTool annotates a tool that changes the live account. destructive marks
+// tools that can delete objects; idempotent marks tools that a repeated call
+// with the same arguments leaves where the first one did. On a dry-run-only
+// server nothing is changed, so the tool is annotated as a read.
+func (w *writeTools) writeTool(title string, destructive, idempotent bool) *mcp.ToolAnnotations {
+	if w.dryRun {
+		return readTool(title)
+	}
+	return &mcp.ToolAnnotations{
+		Title:           title,
+		DestructiveHint: boolPtr(destructive),
+		IdempotentHint:  idempotent,
+		OpenWorldHint:   boolPtr(true),
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,117 @@
+package mcpserver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// annotation is the expected annotation of one tool on a server with writes
+// enabled.
+type annotation struct {
+	title                                        string
+	readOnly, destructive, idempotent, openWorld bool
+}
+
+// toolAnnotations lists every tool the server can register. A new tool must
+// be added here, with its annotation.
+var toolAnnotations = map[string]annotation{
+	"oceanengine_get_advertiser_info":         {"Get advertiser info", true, false, true, true},
+	"oceanengine_list_authorized_advertisers": {"List authorized advertisers", true, false, true, true},
+	"oceanengine_list_campaigns":              {"List campaigns", true, false, true, true},
+	"oceanengine_list_ads":                    {"List ads", true, false, true, true},
+	"oceanengine_list_projects":               {"List projects", true, false, true, true},
+	"oceanengine_list_promotions":             {"List promotions", true, false, true, true},
+	"oceanengine_list_creatives":              {"List creatives", true, false, true, true},
+	"oceanengine_get_creative_details":        {"Get creative details", true, false, true, true},
+	"oceanengine_get_report":                  {"Get report", true, false, true, true},
+	"oceanengine_get_audit_log":               {"Get audit log", true, false, true, false},
+	"oceanengine_update_campaign_status":      {"Update campaign status", false, true, true, true},
+	"oceanengine_update_campaign_budget":      {"Update campaign budget", false, false, true, true},
+	"oceanengine_undo_change":                 {"Undo change", false, false, false, true},
+	"oceanengine_update_ad_status":            {"Update ad status", false, true, true, true},
+	"oceanengine_update_ad_budget":            {"Update ad budget", false, false, true, true},
+	"oceanengine_update_ad_bid":               {"Update ad bid", false, false, true, true},
+	"oceanengine_update_project_status":       {"Update project status", false, true, true, true},
+	"oceanengine_update_project_budget":       {"Update project budget", false, false, true, true},
+	"oceanengine_update_promotion_status":     {"Update promotion status", false, true, true, true},
+	"oceanengine_update_promotion_budget":     {"Update promotion budget", false, false, true, true},
+	"oceanengine_update_promotion_bid":        {"Update promotion bid", false, false, true, true},
+}
+
+func annotationOf(a *mcp.ToolAnnotations) annotation {
+	// Unset pointer hints take the protocol defaults, both true.
+	destructive, openWorld := true, true
+	if a.DestructiveHint != nil {
+		destructive = *a.DestructiveHint
+	}
+	if a.OpenWorldHint != nil {
+		openWorld = *a.OpenWorldHint
+	}
+	return annotation{a.Title, a.ReadOnlyHint, destructive, a.IdempotentHint, openWorld}
+}
+
+func allTools(t *testing.T, cfg Config) []*mcp.Tool {
+	t.Helper()
+	sink, err := NewFileAuditSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sink.Close() })
+	cfg.Audit = sink
+	cs := connect(t, "http://unused", cfg)
+
+	var tools []*mcp.Tool
+	for tool, err := range cs.Tools(context.Background(), nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+func TestToolAnnotations(t *testing.T) {
+	tools := allTools(t, Config{EnableWrites: true})
+	if len(tools) != len(toolAnnotations) {
+		t.Errorf("server registers %d tools, the annotation matrix lists %d", len(tools), len(toolAnnotations))
+	}
+	for _, tool := range tools {
+		want, ok := toolAnnotations[tool.Name]
+		if !ok {
+			t.Errorf("tool %s is missing from the annotation matrix", tool.Name)
+			continue
+		}
+		if tool.Annotations == nil {
+			t.Errorf("tool %s has no annotations", tool.Name)
+			continue
+		}
+		if got := annotationOf(tool.Annotations); got != want {
+			t.Errorf("tool %s annotations = %+v, want %+v", tool.Name, got, want)
+		}
+	}
+}
+
+func TestDryRunToolsAnnotatedReadOnly(t *testing.T) {
+	for _, tool := range allTools(t, Config{DryRun: true}) {
+		if tool.Annotations == nil || !tool.Annotations.ReadOnlyHint {
+			t.Errorf("tool %s on a dry-run-only server should be read-only", tool.Name)
+		}
+	}
+}
+
+func TestWriteToolHintsDiffer(t *testing.T) {
+	got := map[string]annotation{}
+	for _, tool := range allTools(t, Config{EnableWrites: true}) {
+		got[tool.Name] = annotationOf(tool.Annotations)
+	}
+	status, budget, undo := got[toolUpdateStatus], got[toolUpdateBudget], got[toolUndoChange]
+	if !status.destructive || budget.destructive {
+		t.Errorf("status changes can delete and budget changes cannot: status %+v, budget %+v", status, budget)
+	}
+	if !budget.idempotent || undo.idempotent {
+		t.Errorf("repeating a budget change is harmless and repeating an undo is not: budget %+v, undo %+v", budget, undo)
+	}
+}
@@ -0,0 +1,299 @@
+package mcpserver
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// Audit outcomes.
+const (
+	OutcomeApplied = "applied" // Ocean Engine accepted the change
+	OutcomePartial = "partial" // Ocean Engine applied some items of a batch and rejected others
+	OutcomeFailed  = "failed"  // Ocean Engine rejected the change
+	OutcomeRefused = "refused" // not attempted: invalid arguments, access, policy or confirmation
+	OutcomeDryRun  = "dry_run" // previewed only
+)
+
+// AuditEntry records one write tool invocation.
+type AuditEntry struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	// SessionID and Client identify the MCP session and the client software
+	// ("name/version") that made the call; Caller is the authenticated
+	// identity, if the transport has one.
+	SessionID string `json:"session_id,omitempty"`
+	Client    string `json:"client,omitempty"`
+	Caller    string `json:"caller,omitempty"`
+
+	Tool         string         `json:"tool"`
+	AdvertiserID int64          `json:"advertiser_id,omitempty"`
+	Arguments    map[string]any `json:"arguments,omitempty"`
+	// The *Changes fields hold the targeted objects' state before the call
+	// and the state it asked for, when the call got far enough to fetch them.
+	Changes          []CampaignChange  `json:"changes,omitempty"`
+	AdChanges        []AdChange        `json:"ad_changes,omitempty"`
+	ProjectChanges   []ProjectChange   `json:"project_changes,omitempty"`
+	PromotionChanges []PromotionChange `json:"promotion_changes,omitempty"`
+	// Undoes is the ID of the entry an oceanengine_undo_change call reverts.
+	Undoes string `json:"undoes,omitempty"`
+	// RequestIDs are the request_id values of the Ocean Engine write calls.
+	RequestIDs []string `json:"request_ids,omitempty"`
+	// Result is the per-item outcome of a batch write.
+	Result  *oceanengine.BatchResult `json:"result,omitempty"`
+	Outcome string                   `json:"outcome"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+// AuditQuery selects audit entries. Zero fields match everything.
+type AuditQuery struct {
+	ID           string
+	AdvertiserID int64
+	Tool         string
+	Since        time.Time
+	// Limit caps the number of entries returned; 0 means no limit.
+	Limit int
+}
+
+func (q AuditQuery) matches(e *AuditEntry) bool {
+	return (q.ID == "" || e.ID == q.ID) &&
+		(q.AdvertiserID == 0 || e.AdvertiserID == q.AdvertiserID) &&
+		(q.Tool == "" || e.Tool == q.Tool) &&
+		(q.Since.IsZero() || !e.Time.Before(q.Since))
+}
+
+// AuditSink stores audit entries. Implementations must be append-only and safe
+// for concurrent use.
+type AuditSink interface {
+	// Append stores e.
+	Append(ctx context.Context, e *AuditEntry) error
+	// Entries returns the entries matching q, newest first.
+	Entries(ctx context.Context, q AuditQuery) ([]AuditEntry, error)
+}
+
+// FileAuditSink is an AuditSink writing JSON Lines to a file, one entry per
+// line. The file is only ever appended to.
+type FileAuditSink struct {
+	path string
+	mu   sync.Mutex
+	f    *os.File
+}
+
+// NewFileAuditSink opens (or creates) the audit log at path.
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("mcpserver: open audit log: %w", err)
+	}
+	return &FileAuditSink{path: path, f: f}, nil
+}
+
+// Append implements AuditSink. The entry is synced to disk before Append
+// returns.
+func (s *FileAuditSink) Append(_ context.Context, e *AuditEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(line); err != nil {
+		return fmt.Errorf("mcpserver: write audit log: %w", err)
+	}
+	if err := s.f.Sync(); err != nil {
+		return fmt.Errorf("mcpserver: write audit log: %w", err)
+	}
+	return nil
+}
+
+// Entries implements AuditSink by scanning the whole file.
+func (s *FileAuditSink) Entries(ctx context.Context, q AuditQuery) ([]AuditEntry, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("mcpserver: read audit log: %w", err)
+	}
+	defer f.Close()
+
+	var out []AuditEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var e AuditEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			// A torn final line from a crash mid-write; skip it.
+			continue
+		}
+		if q.matches(&e) {
+			out = append(out, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("mcpserver: read audit log: %w", err)
+	}
+	slices.Reverse(out)
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+// Close closes the file.
+func (s *FileAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// newAuditEntry starts the entry for a write tool call.
+func newAuditEntry(req *mcp.CallToolRequest, advertiserID int64) *AuditEntry {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	e := &AuditEntry{
+		ID:           hex.EncodeToString(id[:]),
+		Time:         time.Now().UTC(),
+		Tool:         req.Params.Name,
+		AdvertiserID: advertiserID,
+	}
+	// The SDK has already decoded the arguments into the tool's input, so
+	// this cannot fail in practice.
+	_ = json.Unmarshal(req.Params.Arguments, &e.Arguments)
+	if ss := req.Session; ss != nil {
+		e.SessionID = ss.ID()
+		if p := ss.InitializeParams(); p != nil && p.ClientInfo != nil {
+			e.Client = p.ClientInfo.Name + "/" + p.ClientInfo.Version
+		}
+	}
+	if req.Extra != nil && req.Extra.TokenInfo != nil {
+		e.Caller = req.Extra.TokenInfo.UserID
+	}
+	return e
+}
+
+// audited wraps a write tool handler so that every call, whatever its
+// outcome, is recorded in the audit sink. The handler fills in the entry's
+// changes and request IDs and marks failures that reached Ocean Engine.
+func audited[In any](w *writeTools, advertiserOf func(In) int64,
+	h func(context.Context, *mcp.CallToolRequest, In, *AuditEntry) (*writeOutput, error),
+) mcp.ToolHandlerFor[In, *writeOutput] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, *writeOutput, error) {
+		e := newAuditEntry(req, advertiserOf(in))
+		out, err := h(ctx, req, in, e)
+		switch {
+		case e.Outcome != "":
+			// Set by the handler once Ocean Engine was called.
+		case err != nil:
+			e.Outcome = OutcomeRefused
+		case out.DryRun:
+			e.Outcome = OutcomeDryRun
+		default:
+			e.Outcome = OutcomeApplied
+		}
+		if err != nil {
+			e.Error = err.Error()
+		}
+		if w.audit == nil {
+			return nil, out, err
+		}
+		if aerr := w.audit.Append(context.WithoutCancel(ctx), e); aerr != nil {
+			if e.Outcome == OutcomeApplied || e.Outcome == OutcomePartial {
+				// Do not let the agent retry a change that went through.
+				return nil, nil, fmt.Errorf("the change WAS applied (do not retry), but recording it in the audit log failed: %w", aerr)
+			}
+			return nil, nil, errors.Join(err, fmt.Errorf("recording the call in the audit log failed: %w", aerr))
+		}
+		if out != nil {
+			out.AuditID = e.ID
+		}
+		return nil, out, err
+	}
+}
+
+type getAuditLogInput struct {
+	AdvertiserID int64  `json:"advertiser_id,omitempty" jsonschema:"only changes to this advertiser"`
+	Tool         string `json:"tool,omitempty" jsonschema:"only calls of this tool, e.g. oceanengine_update_campaign_budget"`
+	Since        string `json:"since,omitempty" jsonschema:"only entries at or after this time, RFC 3339 or YYYY-MM-DD"`
+	ID           string `json:"id,omitempty" jsonschema:"a single entry by its ID (the audit_id returned by a write tool)"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"maximum entries to return, newest first; defaults to 20, at most 200"`
+}
+
+type getAuditLogOutput struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+const (
+	defaultAuditLimit = 20
+	maxAuditLimit     = 200
+)
+
+func registerAuditTools(srv *mcp.Server, sink AuditSink, g *guard) {
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        "oceanengine_get_audit_log",
+		Description: "Get recent entries of this server's audit log of write tool calls (who changed what in which Ocean Engine (巨量引擎) account, the state before, the Ocean Engine request_id and the outcome), newest first.",
+		Annotations: localReadTool("Get audit log"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in getAuditLogInput) (*mcp.CallToolResult, getAuditLogOutput, error) {
+		q := AuditQuery{ID: in.ID, AdvertiserID: in.AdvertiserID, Tool: in.Tool, Limit: in.Limit}
+		if q.Limit <= 0 {
+			q.Limit = defaultAuditLimit
+		}
+		q.Limit = min(q.Limit, maxAuditLimit)
+		if in.Since != "" {
+			t, err := parseSince(in.Since)
+			if err != nil {
+				return nil, getAuditLogOutput{}, err
+			}
+			q.Since = t
+		}
+		var ids []int64
+		if in.AdvertiserID != 0 {
+			ids = append(ids, in.AdvertiserID)
+		}
+		if err := g.authorize(req, ids...); err != nil {
+			return nil, getAuditLogOutput{}, err
+		}
+		limit := q.Limit
+		if g.restricted() {
+			// Filter by visibility before applying the limit.
+			q.Limit = 0
+		}
+		entries, err := sink.Entries(ctx, q)
+		if err != nil {
+			return nil, getAuditLogOutput{}, err
+		}
+		visible := g.visibleAdvertisers(req)
+		entries = slices.DeleteFunc(entries, func(e AuditEntry) bool { return !visible(e.AdvertiserID) })
+		if len(entries) > limit {
+			entries = entries[:limit]
+		}
+		if entries == nil {
+			entries = []AuditEntry{}
+		}
+		return nil, getAuditLogOutput{Entries: entries}, nil
+	})
+}
+
+func parseSince(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since must be RFC 3339 (2006-01-02T15:04:05Z) or YYYY-MM-DD")
+	}
+	return t, nil
+}
@@ -0,0 +1,108 @@
+package mcpserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	ctx := context.Background()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, adv := range []int64{1, 2, 1} {
+		if err := sink.Append(ctx, &AuditEntry{ID: string(rune('a' + i)), Time: base.Add(time.Duration(i) * time.Hour), AdvertiserID: adv, Tool: "t", Outcome: OutcomeApplied}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A torn line left by a crash must not break reads.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	_, _ = f.WriteString(`{"id":"torn`)
+	f.Close()
+
+	got, err := sink.Entries(ctx, AuditQuery{AdvertiserID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "c" || got[1].ID != "a" {
+		t.Fatalf("entries = %+v, want c, a (newest first)", got)
+	}
+	got, _ = sink.Entries(ctx, AuditQuery{Since: base.Add(30 * time.Minute), Limit: 1})
+	if len(got) != 1 || got[0].ID != "c" {
+		t.Fatalf("since+limit entries = %+v", got)
+	}
+
+	if mode := fileMode(t, path); mode != 0o600 {
+		t.Fatalf("audit log mode = %o, want 600", mode)
+	}
+}
+
+func fileMode(t *testing.T, path string) os.FileMode {
+	t.Helper()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fi.Mode().Perm()
+}
+
+func TestWritesAreAudited(t *testing.T) {
+	var updates int
+	ts := campaignAPI(t, `[{"id":7,"name":"A","status":"CAMPAIGN_STATUS_ENABLE","budget":400,"budget_mode":"BUDGET_MODE_DAY"}]`, &updates)
+	sink, err := NewFileAuditSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	cs := connect(t, ts.URL, Config{
+		EnableWrites:    true,
+		ConfirmFallback: ConfirmAllow,
+		Policy:          WritePolicy{ForbidDelete: true},
+		Audit:           sink,
+	})
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	res := call("oceanengine_update_campaign_budget", map[string]any{"advertiser_id": 1, "campaign_id": 7, "budget": 500})
+	var out writeOutput
+	decodeStructured(t, res, &out)
+	if out.AuditID == "" {
+		t.Fatalf("write output has no audit_id: %+v", out)
+	}
+	call("oceanengine_update_campaign_status", map[string]any{"advertiser_id": 1, "campaign_ids": []int64{7}, "opt_status": "delete"})
+
+	res = call("oceanengine_get_audit_log", map[string]any{"advertiser_id": 1})
+	var log getAuditLogOutput
+	decodeStructured(t, res, &log)
+	if len(log.Entries) != 2 {
+		t.Fatalf("audit entries = %+v, want 2", log.Entries)
+	}
+	refused, applied := log.Entries[0], log.Entries[1]
+	if refused.Outcome != OutcomeRefused || !strings.Contains(refused.Error, "deleting campaigns is disabled") {
+		t.Errorf("refused entry = %+v", refused)
+	}
+	if applied.ID != out.AuditID || applied.Outcome != OutcomeApplied || applied.Tool != "oceanengine_update_campaign_budget" ||
+		len(applied.RequestIDs) != 1 || applied.RequestIDs[0] != "req-w" ||
+		applied.Changes[0].Before.Budget != 400 || applied.Client != "test/v0" ||
+		applied.Arguments["budget"] != 500.0 {
+		t.Errorf("applied entry = %+v", applied)
+	}
+}
@@ -0,0 +1,152 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ConfirmFallback decides what happens to a write when the connected client
+// cannot ask its user to confirm it, because it does not support MCP
+// elicitation.
+type ConfirmFallback int
+
+const (
+	// ConfirmRefuse refuses unconfirmed writes — the safe default.
+	ConfirmRefuse ConfirmFallback = iota
+	// ConfirmAllow applies unconfirmed writes, relying on the client's own
+	// tool-call approval.
+	ConfirmAllow
+)
+
+// ParseConfirmFallback parses "refuse" or "allow".
+func ParseConfirmFallback(s string) (ConfirmFallback, error) {
+	switch s {
+	case "refuse":
+		return ConfirmRefuse, nil
+	case "allow":
+		return ConfirmAllow, nil
+	}
+	return 0, fmt.Errorf("confirm fallback must be refuse or allow, not %q", s)
+}
+
+// confirmSchema requests no input: the user only accepts or declines.
+var confirmSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+
+// confirm asks the user behind req's session to confirm summary and returns
+// an error unless they accept.
+func (w *writeTools) confirm(ctx context.Context, req *mcp.CallToolRequest, summary string) error {
+	if !canElicit(req.Session) {
+		if w.confirmFallback == ConfirmAllow {
+			return nil
+		}
+		return fmt.Errorf("refused: this write needs the user's confirmation, but the MCP client does not support elicitation (%s)", summary)
+	}
+	res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+		Message:         summary + "\n\nApply this change to the live account?",
+		RequestedSchema: confirmSchema,
+	})
+	if err != nil {
+		return fmt.Errorf("could not get the user's confirmation, nothing was changed: %w", err)
+	}
+	if res.Action != "accept" {
+		return fmt.Errorf("the user did not confirm the change (%s), nothing was changed: %s", res.Action, summary)
+	}
+	return nil
+}
+
+func canElicit(ss *mcp.ServerSession) bool {
+	if ss == nil {
+		return false
+	}
+	p := ss.InitializeParams()
+	return p != nil && p.Capabilities != nil && p.Capabilities.Elicitation != nil
+}
+
+// statusSummary describes a status change of campaigns or ads (kind) for the
+// user, e.g. "Disable 3 campaigns in account 123: A, B, C".
+func statusSummary(advertiserID int64, optStatus, kind string, labels []string) string {
+	verb := strings.ToUpper(optStatus[:1]) + optStatus[1:]
+	return fmt.Sprintf("%s %s in account %d: %s", verb, count(len(labels), kind), advertiserID, names(labels))
+}
+
+// budgetSummary describes a budget change for the user.
+func budgetSummary(advertiserID int64, c CampaignChange) string {
+	return fmt.Sprintf("Change the budget of campaign %s in account %d from %s to %s",
+		campaignLabel(c), advertiserID, budgetString(c.Before), budgetString(c.After))
+}
+
+// valueSummary describes a change of one value (what) of several campaigns,
+// ads, projects or promotions (kind) for the user, e.g. "Change the bid of 2
+// ads in account 123: A from 1.5 to 2, B from 3 to 2.5". items come from
+// fromTo.
+func valueSummary(advertiserID int64, what, kind string, items []string) string {
+	return fmt.Sprintf("Change the %s of %s in account %d: %s", what, count(len(items), kind), advertiserID, names(items))
+}
+
+func fromTo(label, before, after string) string {
+	return fmt.Sprintf("%s from %s to %s", label, before, after)
+}
+
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// maxNamesInSummary keeps confirmation prompts readable for large batches.
+const maxNamesInSummary = 10
+
+func names(labels []string) string {
+	if len(labels) > maxNamesInSummary {
+		labels = append(labels[:maxNamesInSummary:maxNamesInSummary], fmt.Sprintf("and %d more", len(labels)-maxNamesInSummary))
+	}
+	return strings.Join(labels, ", ")
+}
+
+func campaignLabels(changes []CampaignChange) []string {
+	labels := make([]string, len(changes))
+	for i, c := range changes {
+		labels[i] = campaignLabel(c)
+	}
+	return labels
+}
+
+func campaignLabel(c CampaignChange) string {
+	return label(c.CampaignName, c.CampaignID)
+}
+
+func adLabels(changes []AdChange) []string {
+	labels := make([]string, len(changes))
+	for i, c := range changes {
+		labels[i] = adLabel(c)
+	}
+	return labels
+}
+
+func adLabel(c AdChange) string {
+	return label(c.AdName, c.AdID)
+}
+
+// label names an object for the user by its name, or its ID if it has none.
+func label(name string, id int64) string {
+	if name == "" {
+		return strconv.FormatInt(id, 10)
+	}
+	return name
+}
+
+func budgetString(s State) string {
+	if s.BudgetMode == budgetModeInfinite {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%g (%s)", s.Budget, s.BudgetMode)
+}
+
+func bidString(s State) string {
+	return strconv.FormatFloat(s.Bid, 'g', -1, 64)
+}
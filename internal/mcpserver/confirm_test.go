@@ -0,0 +1,87 @@
+package mcpserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+const twoCampaigns = `[
+	{"id":7,"name":"A","status":"CAMPAIGN_STATUS_ENABLE"},
+	{"id":8,"name":"B","status":"CAMPAIGN_STATUS_ENABLE"}]`
+
+func disableBoth(t *testing.T, cs *mcp.ClientSession) *mcp.CallToolResult {
+	t.Helper()
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_update_campaign_status",
+		Arguments: map[string]any{"advertiser_id": 1, "campaign_ids": []int64{7, 8}, "opt_status": "disable"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestConfirmWithElicitation(t *testing.T) {
+	for _, action := range []string{"accept", "decline", "cancel"} {
+		t.Run(action, func(t *testing.T) {
+			var updates int
+			ts := campaignAPI(t, twoCampaigns, &updates)
+
+			var asked string
+			opts := &mcp.ClientOptions{
+				ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+					asked = req.Params.Message
+					return &mcp.ElicitResult{Action: action}, nil
+				},
+			}
+			client := oceanengine.NewClient("tok", oceanengine.WithBaseURL(ts.URL))
+			cs := connectWithOptions(t, client, Config{EnableWrites: true}, opts)
+
+			res := disableBoth(t, cs)
+			if !strings.Contains(asked, "Disable 2 campaigns in account 1: A, B") {
+				t.Fatalf("confirmation prompt = %q", asked)
+			}
+			if action == "accept" {
+				if res.IsError || updates != 1 {
+					t.Fatalf("accepted write: updates = %d, result %+v", updates, res.Content)
+				}
+				return
+			}
+			if !res.IsError || updates != 0 {
+				t.Fatalf("%s: updates = %d, result %+v; want refusal", action, updates, res.Content)
+			}
+		})
+	}
+}
+
+func TestConfirmFallback(t *testing.T) {
+	var updates int
+	ts := campaignAPI(t, twoCampaigns, &updates)
+
+	// The default test client does not support elicitation.
+	res := disableBoth(t, connect(t, ts.URL, Config{EnableWrites: true}))
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "does not support elicitation") || updates != 0 {
+		t.Fatalf("refuse fallback: updates = %d, result %+v", updates, res.Content)
+	}
+
+	res = disableBoth(t, connect(t, ts.URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow}))
+	if res.IsError || updates != 1 {
+		t.Fatalf("allow fallback: updates = %d, result %+v", updates, res.Content)
+	}
+}
+
+func TestSummaryTruncatesNames(t *testing.T) {
+	changes := make([]CampaignChange, 12)
+	for i := range changes {
+		changes[i] = CampaignChange{CampaignID: int64(i + 1)}
+	}
+	got := statusSummary(5, "delete", "campaign", campaignLabels(changes))
+	if !strings.HasPrefix(got, "Delete 12 campaigns in account 5: 1, 2,") || !strings.HasSuffix(got, "10, and 2 more") {
+		t.Fatalf("summary = %q", got)
+	}
+}
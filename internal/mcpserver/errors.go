@@ -0,0 +1,34 @@
+package mcpserver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// toolError turns an Ocean Engine client error into a tool error that tells
+// the agent (and the user behind it) what to do next. The original error stays
+// wrapped so its code and request_id remain visible.
+func toolError(err error) error {
+	var hint string
+	switch {
+	case oceanengine.IsTokenExpired(err):
+		hint = "token expired — re-authorize (oceanengine-mcp auth login) and restart the server"
+	case errors.Is(err, oceanengine.ErrAuthInvalid):
+		hint = "access token rejected — check the server's Ocean Engine credentials or re-authorize"
+	case oceanengine.IsPermissionDenied(err):
+		hint = "permission denied — the authorized user cannot access this advertiser; check the advertiser_id"
+	case oceanengine.IsRateLimited(err):
+		hint = "rate limited by Ocean Engine — wait a moment and retry, or request fewer pages"
+	case errors.Is(err, oceanengine.ErrInvalidParameter):
+		hint = "invalid parameter — check the tool arguments"
+	case errors.Is(err, oceanengine.ErrNotFound):
+		hint = "not found — the referenced advertiser, campaign or ad does not exist"
+	case errors.Is(err, oceanengine.ErrSystem):
+		hint = "Ocean Engine system error — retry later"
+	default:
+		return err
+	}
+	return fmt.Errorf("%s: %w", hint, err)
+}
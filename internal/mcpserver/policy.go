@@ -0,0 +1,143 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// WritePolicy bounds what the write tools may do to the live account. Writes
+// outside the policy are refused with an explanation before Ocean Engine is
+// called. Zero fields impose no limit.
+type WritePolicy struct {
+	// MaxBudget caps the budget a campaign, ad, project or promotion may be
+	// set to. It also forbids switching a campaign to an unlimited budget.
+	MaxBudget float64
+	// MaxBudgetChangePercent caps a budget change relative to the current
+	// budget, in either direction: 50 allows 100 → 150 or 100 → 50. It does
+	// not apply to objects whose current budget is unlimited.
+	MaxBudgetChangePercent float64
+	// MaxBid caps the bid an ad or promotion may be set to.
+	MaxBid float64
+	// MaxCampaignsPerCall caps the campaigns, ads, projects or promotions one
+	// call may change.
+	MaxCampaignsPerCall int
+	// ForbidDelete refuses opt_status "delete" altogether.
+	ForbidDelete bool
+}
+
+const budgetModeInfinite = "BUDGET_MODE_INFINITE"
+
+// finiteBudgetMode is the budget mode a new budget amount takes effect in when
+// only the amount is written: the object's current mode, or a daily budget if
+// it had an unlimited one.
+func finiteBudgetMode(cur string) string {
+	if cur == budgetModeInfinite {
+		return "BUDGET_MODE_DAY"
+	}
+	return cur
+}
+
+// checkCount checks a change of n campaigns or ads (noun) against the policy.
+func (p WritePolicy) checkCount(noun string, n int) error {
+	if p.MaxCampaignsPerCall > 0 && n > p.MaxCampaignsPerCall {
+		return fmt.Errorf("refused by write policy: %d %s in one call exceeds the limit of %d; split the change into smaller calls", n, noun, p.MaxCampaignsPerCall)
+	}
+	return nil
+}
+
+// checkStatus checks a status change of n campaigns or ads (noun) against the
+// policy.
+func (p WritePolicy) checkStatus(noun, optStatus string, n int) error {
+	if p.ForbidDelete && optStatus == "delete" {
+		return fmt.Errorf("refused by write policy: deleting %s is disabled on this server; disable them instead", noun)
+	}
+	return p.checkCount(noun, n)
+}
+
+// checkBudget checks a change of the budget of the campaign or ad described by
+// label, currently cur, to budget (in budgetMode) against the policy.
+func (p WritePolicy) checkBudget(label string, cur State, budget float64, budgetMode string) error {
+	if p.MaxBudget > 0 {
+		if budgetMode == budgetModeInfinite {
+			return fmt.Errorf("refused by write policy: an unlimited budget exceeds the maximum budget of %g", p.MaxBudget)
+		}
+		if budget > p.MaxBudget {
+			return fmt.Errorf("refused by write policy: budget %g exceeds the maximum budget of %g", budget, p.MaxBudget)
+		}
+	}
+	if p.MaxBudgetChangePercent > 0 && cur.BudgetMode != budgetModeInfinite && cur.Budget > 0 && budgetMode != budgetModeInfinite {
+		change := math.Abs(budget-cur.Budget) / cur.Budget * 100
+		if change > p.MaxBudgetChangePercent {
+			lo := cur.Budget * (1 - p.MaxBudgetChangePercent/100)
+			hi := cur.Budget * (1 + p.MaxBudgetChangePercent/100)
+			return fmt.Errorf("refused by write policy: changing %s's budget from %g to %g is a %.0f%% change, above the limit of %g%%; choose a budget between %g and %g",
+				label, cur.Budget, budget, change, p.MaxBudgetChangePercent, math.Max(lo, 0), hi)
+		}
+	}
+	return nil
+}
+
+// checkBid checks a new bid for the ad described by label against the policy.
+func (p WritePolicy) checkBid(label string, bid float64) error {
+	if p.MaxBid > 0 && bid > p.MaxBid {
+		return fmt.Errorf("refused by write policy: bid %g for %s exceeds the maximum bid of %g", bid, label, p.MaxBid)
+	}
+	return nil
+}
+
+// currentCampaigns fetches the current state of the given campaigns, failing
+// if any of them does not exist in the advertiser.
+func currentCampaigns(ctx context.Context, client *oceanengine.Client, advertiserID int64, ids []int64) (map[int64]oceanengine.Campaign, error) {
+	list, err := client.GetCampaigns(ctx, advertiserID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return byID("campaign", advertiserID, ids, list, func(c oceanengine.Campaign) int64 { return c.ID })
+}
+
+// currentAds fetches the current state of the given ads, failing if any of
+// them does not exist in the advertiser.
+func currentAds(ctx context.Context, client *oceanengine.Client, advertiserID int64, ids []int64) (map[int64]oceanengine.Ad, error) {
+	list, err := client.GetAds(ctx, advertiserID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return byID("ad", advertiserID, ids, list, func(a oceanengine.Ad) int64 { return a.ID })
+}
+
+// currentProjects fetches the current state of the given projects, failing
+// if any of them does not exist in the advertiser.
+func currentProjects(ctx context.Context, client *oceanengine.Client, advertiserID int64, ids []int64) (map[int64]oceanengine.Project, error) {
+	list, err := client.GetProjects(ctx, advertiserID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return byID("project", advertiserID, ids, list, func(p oceanengine.Project) int64 { return p.ID })
+}
+
+// currentPromotions fetches the current state of the given promotions,
+// failing if any of them does not exist in the advertiser.
+func currentPromotions(ctx context.Context, client *oceanengine.Client, advertiserID int64, ids []int64) (map[int64]oceanengine.Promotion, error) {
+	list, err := client.GetPromotions(ctx, advertiserID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return byID("promotion", advertiserID, ids, list, func(p oceanengine.Promotion) int64 { return p.ID })
+}
+
+// byID indexes items by ID, failing if any of ids is missing.
+func byID[T any](kind string, advertiserID int64, ids []int64, items []T, id func(T) int64) (map[int64]T, error) {
+	out := make(map[int64]T, len(items))
+	for _, item := range items {
+		out[id(item)] = item
+	}
+	for _, want := range ids {
+		if _, ok := out[want]; !ok {
+			return nil, fmt.Errorf("%s %d not found in advertiser %d", kind, want, advertiserID)
+		}
+	}
+	return out, nil
+}
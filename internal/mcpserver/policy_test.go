@@ -0,0 +1,103 @@
+package mcpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWritePolicyBudget(t *testing.T) {
+	p := WritePolicy{MaxBudget: 1000, MaxBudgetChangePercent: 50}
+	cur := State{Budget: 400, BudgetMode: "BUDGET_MODE_DAY"}
+	for _, tc := range []struct {
+		budget float64
+		mode   string
+		want   string // substring of the error, "" for allowed
+	}{
+		{550, "BUDGET_MODE_DAY", ""},
+		{200, "BUDGET_MODE_DAY", ""},
+		{700, "BUDGET_MODE_DAY", "between 200 and 600"},
+		{100, "BUDGET_MODE_DAY", "75% change"},
+		{1200, "BUDGET_MODE_DAY", "maximum budget of 1000"},
+		{0, budgetModeInfinite, "unlimited budget"},
+	} {
+		err := p.checkBudget("campaign 7", cur, tc.budget, tc.mode)
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("budget %g: unexpected error %v", tc.budget, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("budget %g %s: err = %v, want it to mention %q", tc.budget, tc.mode, err, tc.want)
+		}
+	}
+
+	// The percentage cap cannot apply to a currently unlimited budget.
+	unlimited := State{BudgetMode: budgetModeInfinite}
+	if err := p.checkBudget("campaign 7", unlimited, 900, "BUDGET_MODE_DAY"); err != nil {
+		t.Errorf("capping an unlimited budget: %v", err)
+	}
+}
+
+func TestWritePolicyStatus(t *testing.T) {
+	p := WritePolicy{MaxCampaignsPerCall: 2, ForbidDelete: true}
+	if err := p.checkStatus("campaigns", "disable", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.checkStatus("campaigns", "disable", 3); err == nil || !strings.Contains(err.Error(), "limit of 2") {
+		t.Errorf("3 campaigns: err = %v", err)
+	}
+	if err := p.checkStatus("campaigns", "delete", 1); err == nil || !strings.Contains(err.Error(), "deleting campaigns is disabled") {
+		t.Errorf("delete: err = %v", err)
+	}
+	if err := (WritePolicy{}).checkStatus("campaigns", "delete", 500); err != nil {
+		t.Errorf("zero policy should allow everything: %v", err)
+	}
+}
+
+func TestBudgetToolEnforcesPolicy(t *testing.T) {
+	var updates int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open_api/2/campaign/get/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"id":7,"budget":400,"budget_mode":"BUDGET_MODE_DAY"}],"page_info":{"page":1,"total_page":1}}}`))
+		case "/open_api/2/campaign/update/budget/":
+			updates++
+			_, _ = w.Write([]byte(`{"code":0,"data":{}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	cs := connect(t, ts.URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow, Policy: WritePolicy{MaxBudgetChangePercent: 50}})
+	call := func(budget float64) *mcp.CallToolResult {
+		t.Helper()
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "oceanengine_update_campaign_budget",
+			Arguments: map[string]any{"advertiser_id": 1, "campaign_id": 7, "budget": budget},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := call(2000); !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "refused by write policy") {
+		t.Fatalf("out-of-policy budget: %+v", res.Content)
+	}
+	if updates != 0 {
+		t.Fatal("refused write reached Ocean Engine")
+	}
+	if res := call(500); res.IsError {
+		t.Fatalf("in-policy budget: %+v", res.Content)
+	}
+	if updates != 1 {
+		t.Fatalf("updates = %d, want 1", updates)
+	}
+}
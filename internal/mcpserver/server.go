@@ -6,6 +6,9 @@ package mcpserver
 import (
 	"context"
 	"fmt"
+	"iter"
+	"slices"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -20,6 +23,28 @@ type Config struct {
 	// EnableWrites registers the mutating tools (status/budget changes). When
 	// false, the server is read-only — the safe default.
 	EnableWrites bool
+	// AllowedAdvertiserIDs, if non-empty, confines every tool — reads and
+	// writes — to these advertisers, whatever the token could reach.
+	AllowedAdvertiserIDs []int64
+	// Policy bounds what the write tools may do; see WritePolicy.
+	Policy WritePolicy
+	// DryRun makes every write tool call a dry run: inputs are validated and
+	// the before/after diff is returned, but nothing is changed. Calls can
+	// also ask for a dry run with their dry_run argument. With EnableWrites
+	// false, DryRun registers the write tools for rehearsal only.
+	DryRun bool
+	// ConfirmFallback decides writes the user cannot be asked to confirm
+	// because the client does not support elicitation. Clients that do
+	// support it always ask.
+	ConfirmFallback ConfirmFallback
+	// Audit, if set, records every write tool call and enables the
+	// oceanengine_get_audit_log tool.
+	Audit AuditSink
+	// Grants maps caller identities — the UserID of the request's bearer
+	// token, see auth.TokenInfo — to what each caller may do. When non-nil,
+	// calls from callers without an entry are refused. Leave nil when the
+	// transport does not authenticate callers (stdio).
+	Grants map[string]Grant
 }
 
 // New builds an MCP server exposing Ocean Engine tools backed by client.
@@ -31,10 +56,15 @@ func New(client *oceanengine.Client, cfg Config) *mcp.Server {
 		cfg.Version = "dev"
 	}
 
+	g := &guard{allowed: cfg.AllowedAdvertiserIDs, grants: cfg.Grants}
 	srv := mcp.NewServer(&mcp.Implementation{Name: cfg.Name, Version: cfg.Version}, nil)
-	registerReadTools(srv, client)
-	if cfg.EnableWrites {
-		registerWriteTools(srv, client)
+	srv.AddReceivingMiddleware(g.filterToolList)
+	registerReadTools(srv, client, g)
+	if cfg.Audit != nil {
+		registerAuditTools(srv, cfg.Audit, g)
+	}
+	if cfg.EnableWrites || cfg.DryRun {
+		registerWriteTools(srv, client, g, cfg)
 	}
 	return srv
 }
@@ -52,151 +82,558 @@ type advertiserInfoOutput struct {
 	Advertisers []oceanengine.Advertiser `json:"advertisers"`
 }
 
+type listAuthorizedInput struct {
+	IncludeChildren bool `json:"include_children,omitempty" jsonschema:"also list the child accounts of majordomo (账号管家) and agency accounts"`
+}
+
+type authorizedAdvertiser struct {
+	AdvertiserID   int64  `json:"advertiser_id"`
+	AdvertiserName string `json:"advertiser_name,omitempty"`
+	AccountRole    string `json:"account_role"`
+	ParentID       int64  `json:"parent_id,omitempty"`
+	User           string `json:"user,omitempty"`
+}
+
+type listAuthorizedOutput struct {
+	Advertisers []authorizedAdvertiser `json:"advertisers"`
+}
+
 type listCampaignsInput struct {
-	AdvertiserID int64 `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
-	Page         int   `json:"page,omitempty" jsonschema:"1-based page number; defaults to 1"`
-	PageSize     int   `json:"page_size,omitempty" jsonschema:"page size 1-100; defaults to 10"`
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	CampaignIDs  []int64 `json:"campaign_ids,omitempty" jsonschema:"only these campaign IDs"`
+	Name         string  `json:"name,omitempty" jsonschema:"campaign name keyword (fuzzy match)"`
+	Status       string  `json:"status,omitempty" jsonschema:"campaign status, e.g. CAMPAIGN_STATUS_ENABLE, CAMPAIGN_STATUS_DISABLE"`
+	LandingType  string  `json:"landing_type,omitempty" jsonschema:"landing type, e.g. LINK, APP, SHOP"`
+	CreateDate   string  `json:"create_date,omitempty" jsonschema:"only campaigns created on this day, YYYY-MM-DD"`
+	Page         int     `json:"page,omitempty" jsonschema:"1-based page number; defaults to 1"`
+	PageSize     int     `json:"page_size,omitempty" jsonschema:"page size 1-100; defaults to 10"`
+	AllPages     bool    `json:"all_pages,omitempty" jsonschema:"fetch every page instead of one; page and page_size are then ignored"`
+	MaxItems     int     `json:"max_items,omitempty" jsonschema:"with all_pages, stop after this many items; defaults to 1000"`
+}
+
+type listCampaignsOutput struct {
+	oceanengine.CampaignList
+	Truncated bool `json:"truncated,omitempty" jsonschema:"true if all_pages stopped at max_items before the last page"`
 }
 
 type listAdsInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	AdIDs        []int64 `json:"ad_ids,omitempty" jsonschema:"only these ad IDs"`
+	CampaignID   int64   `json:"campaign_id,omitempty" jsonschema:"only ads in this campaign"`
+	Name         string  `json:"name,omitempty" jsonschema:"ad name keyword (fuzzy match)"`
+	Status       string  `json:"status,omitempty" jsonschema:"ad status, e.g. AD_STATUS_DELIVERY_OK, AD_STATUS_DISABLE"`
+	CreateDate   string  `json:"create_date,omitempty" jsonschema:"only ads created on this day, YYYY-MM-DD"`
+	ModifyHour   string  `json:"modify_hour,omitempty" jsonschema:"only ads modified in this hour, YYYY-MM-DD HH"`
+	Page         int     `json:"page,omitempty" jsonschema:"1-based page number; defaults to 1"`
+	PageSize     int     `json:"page_size,omitempty" jsonschema:"page size 1-100; defaults to 10"`
+	AllPages     bool    `json:"all_pages,omitempty" jsonschema:"fetch every page instead of one; page and page_size are then ignored"`
+	MaxItems     int     `json:"max_items,omitempty" jsonschema:"with all_pages, stop after this many items; defaults to 1000"`
+}
+
+type listAdsOutput struct {
+	oceanengine.AdList
+	Truncated bool `json:"truncated,omitempty" jsonschema:"true if all_pages stopped at max_items before the last page"`
+}
+
+type listCreativesInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	AdID         int64   `json:"ad_id,omitempty" jsonschema:"only creatives of this ad"`
+	CreativeIDs  []int64 `json:"creative_ids,omitempty" jsonschema:"only these creative IDs"`
+	CampaignID   int64   `json:"campaign_id,omitempty" jsonschema:"only creatives in this campaign"`
+	Status       string  `json:"status,omitempty" jsonschema:"creative status, e.g. CREATIVE_STATUS_DELIVERY_OK, CREATIVE_STATUS_AUDIT_DENY"`
+	Page         int     `json:"page,omitempty" jsonschema:"1-based page number; defaults to 1"`
+	PageSize     int     `json:"page_size,omitempty" jsonschema:"page size 1-100; defaults to 10"`
+	AllPages     bool    `json:"all_pages,omitempty" jsonschema:"fetch every page instead of one; page and page_size are then ignored"`
+	MaxItems     int     `json:"max_items,omitempty" jsonschema:"with all_pages, stop after this many items; defaults to 1000"`
+}
+
+type listCreativesOutput struct {
+	oceanengine.CreativeList
+	Truncated bool `json:"truncated,omitempty" jsonschema:"true if all_pages stopped at max_items before the last page"`
+}
+
+type getCreativeDetailsInput struct {
 	AdvertiserID int64 `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
-	Page         int   `json:"page,omitempty" jsonschema:"1-based page number; defaults to 1"`
-	PageSize     int   `json:"page_size,omitempty" jsonschema:"page size 1-100; defaults to 10"`
+	AdID         int64 `json:"ad_id" jsonschema:"the ad whose creatives to fetch"`
+}
+
+type getCreativeDetailsOutput struct {
+	oceanengine.CreativeDetail
+}
+
+type listProjectsInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	ProjectIDs   []int64 `json:"project_ids,omitempty" jsonschema:"only these project IDs"`
+	Name         string  `json:"name,omitempty" jsonschema:"project name keyword (fuzzy match)"`
+	StatusFirst  string  `json:"status_first,omitempty" jsonschema:"project status, e.g. PROJECT_STATUS_ENABLE, PROJECT_STATUS_DISABLE"`
+	LandingType  string  `json:"landing_type,omitempty" jsonschema:"landing type, e.g. LINK, APP, MICRO_GAME"`
+	DeliveryMode string  `json:"delivery_mode,omitempty" jsonschema:"MANUAL or PROCEDURAL"`
+	Page         int     `json:"page,omitempty" jsonschema:"1-based page number; defaults to 1"`
+	PageSize     int     `json:"page_size,omitempty" jsonschema:"page size 1-100; defaults to 10"`
+	AllPages     bool    `json:"all_pages,omitempty" jsonschema:"fetch every page instead of one; page and page_size are then ignored"`
+	MaxItems     int     `json:"max_items,omitempty" jsonschema:"with all_pages, stop after this many items; defaults to 1000"`
+}
+
+type listProjectsOutput struct {
+	oceanengine.ProjectList
+	Truncated bool `json:"truncated,omitempty" jsonschema:"true if all_pages stopped at max_items before the last page"`
+}
+
+type listPromotionsInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	PromotionIDs []int64 `json:"promotion_ids,omitempty" jsonschema:"only these promotion IDs"`
+	ProjectID    int64   `json:"project_id,omitempty" jsonschema:"only promotions in this project"`
+	Name         string  `json:"name,omitempty" jsonschema:"promotion name keyword (fuzzy match)"`
+	StatusFirst  string  `json:"status_first,omitempty" jsonschema:"promotion status, e.g. PROMOTION_STATUS_ENABLE, PROMOTION_STATUS_DISABLE"`
+	Page         int     `json:"page,omitempty" jsonschema:"1-based page number; defaults to 1"`
+	PageSize     int     `json:"page_size,omitempty" jsonschema:"page size 1-100; defaults to 10"`
+	AllPages     bool    `json:"all_pages,omitempty" jsonschema:"fetch every page instead of one; page and page_size are then ignored"`
+	MaxItems     int     `json:"max_items,omitempty" jsonschema:"with all_pages, stop after this many items; defaults to 1000"`
+}
+
+type listPromotionsOutput struct {
+	oceanengine.PromotionList
+	Truncated bool `json:"truncated,omitempty" jsonschema:"true if all_pages stopped at max_items before the last page"`
 }
 
 type getReportInput struct {
 	AdvertiserID int64    `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	Level        string   `json:"level,omitempty" jsonschema:"what each row reports on: advertiser, campaign, ad or creative; defaults to ad"`
 	StartDate    string   `json:"start_date" jsonschema:"report start date, YYYY-MM-DD"`
 	EndDate      string   `json:"end_date" jsonschema:"report end date, YYYY-MM-DD"`
 	GroupBy      []string `json:"group_by,omitempty" jsonschema:"dimensions to group by, e.g. [\"STAT_GROUP_BY_FIELD_ID\",\"STAT_GROUP_BY_FIELD_STAT_TIME\"]"`
-	Fields       []string `json:"fields,omitempty" jsonschema:"metrics to return, e.g. [\"cost\",\"show\",\"click\",\"convert\"]"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"metrics to return, e.g. [\"cost\",\"show\",\"click\",\"convert\"]; defaults to the main metrics of the level"`
 	Page         int      `json:"page,omitempty" jsonschema:"1-based page number; defaults to 1"`
 	PageSize     int      `json:"page_size,omitempty" jsonschema:"page size 1-100; defaults to 10"`
+	AllPages     bool     `json:"all_pages,omitempty" jsonschema:"fetch every page instead of one; page and page_size are then ignored"`
+	MaxItems     int      `json:"max_items,omitempty" jsonschema:"with all_pages, stop after this many rows; defaults to 1000"`
+}
+
+type getReportOutput struct {
+	oceanengine.ReportResult
+	Truncated bool `json:"truncated,omitempty" jsonschema:"true if all_pages stopped at max_items before the last page"`
 }
 
-func registerReadTools(srv *mcp.Server, client *oceanengine.Client) {
+func registerReadTools(srv *mcp.Server, client *oceanengine.Client, g *guard) {
 	mcp.AddTool(srv, &mcp.Tool{
 		Name:        "oceanengine_get_advertiser_info",
 		Description: "Get Ocean Engine (巨量引擎) advertiser account information by advertiser ID.",
-	}, func(ctx context.Context, _ *mcp.CallToolRequest, in advertiserInfoInput) (*mcp.CallToolResult, advertiserInfoOutput, error) {
+		Annotations: readTool("Get advertiser info"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in advertiserInfoInput) (*mcp.CallToolResult, advertiserInfoOutput, error) {
 		if len(in.AdvertiserIDs) == 0 {
 			return nil, advertiserInfoOutput{}, fmt.Errorf("advertiser_ids must not be empty")
 		}
+		if err := g.authorize(req, in.AdvertiserIDs...); err != nil {
+			return nil, advertiserInfoOutput{}, err
+		}
 		ads, err := client.GetAdvertiserInfo(ctx, in.AdvertiserIDs, in.Fields)
 		if err != nil {
-			return nil, advertiserInfoOutput{}, err
+			return nil, advertiserInfoOutput{}, toolError(err)
 		}
 		return nil, advertiserInfoOutput{Advertisers: ads}, nil
 	})
 
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        "oceanengine_list_authorized_advertisers",
+		Description: "List the Ocean Engine (巨量引擎) advertiser accounts this server is authorized for, with name and account role; optionally include the child accounts of majordomo/agency accounts. Use it to find valid advertiser_id values.",
+		Annotations: readTool("List authorized advertisers"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in listAuthorizedInput) (*mcp.CallToolResult, listAuthorizedOutput, error) {
+		if err := g.authorize(req); err != nil {
+			return nil, listAuthorizedOutput{}, err
+		}
+		res, err := listAuthorized(ctx, client, in.IncludeChildren)
+		if err != nil {
+			return nil, listAuthorizedOutput{}, toolError(err)
+		}
+		visible := g.visibleAdvertisers(req)
+		res = slices.DeleteFunc(res, func(a authorizedAdvertiser) bool { return !visible(a.AdvertiserID) })
+		return nil, listAuthorizedOutput{Advertisers: res}, nil
+	})
+
 	mcp.AddTool(srv, &mcp.Tool{
 		Name:        "oceanengine_list_campaigns",
-		Description: "List Ocean Engine (巨量引擎) campaigns (广告组) for an advertiser, with pagination.",
-	}, func(ctx context.Context, _ *mcp.CallToolRequest, in listCampaignsInput) (*mcp.CallToolResult, *oceanengine.CampaignList, error) {
+		Description: "List Ocean Engine (巨量引擎) campaigns (广告组) for an advertiser, optionally filtered by ID, name, status, landing type or creation day, with pagination.",
+		Annotations: readTool("List campaigns"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in listCampaignsInput) (*mcp.CallToolResult, *listCampaignsOutput, error) {
 		if in.AdvertiserID == 0 {
 			return nil, nil, fmt.Errorf("advertiser_id is required")
 		}
-		res, err := client.ListCampaigns(ctx, in.AdvertiserID, in.Page, in.PageSize)
-		if err != nil {
+		if err := g.authorize(req, in.AdvertiserID); err != nil {
 			return nil, nil, err
 		}
-		return nil, res, nil
+		filter := &oceanengine.CampaignFilter{
+			IDs:         in.CampaignIDs,
+			Name:        in.Name,
+			Status:      in.Status,
+			LandingType: in.LandingType,
+			CreateTime:  in.CreateDate,
+		}
+		if in.AllPages {
+			limit := maxItems(in.MaxItems)
+			list, truncated, err := collect(client.AllCampaigns(ctx, in.AdvertiserID, filter, limit+1), limit)
+			if err != nil {
+				return nil, nil, toolError(err)
+			}
+			return nil, &listCampaignsOutput{
+				CampaignList: oceanengine.CampaignList{List: list, PageInfo: allPagesInfo(len(list))},
+				Truncated:    truncated,
+			}, nil
+		}
+		res, err := client.ListCampaigns(ctx, in.AdvertiserID, filter, in.Page, in.PageSize)
+		if err != nil {
+			return nil, nil, toolError(err)
+		}
+		return nil, &listCampaignsOutput{CampaignList: *res}, nil
 	})
 
 	mcp.AddTool(srv, &mcp.Tool{
 		Name:        "oceanengine_list_ads",
-		Description: "List Ocean Engine (巨量引擎) ads (广告计划) for an advertiser, with pagination.",
-	}, func(ctx context.Context, _ *mcp.CallToolRequest, in listAdsInput) (*mcp.CallToolResult, *oceanengine.AdList, error) {
+		Description: "List Ocean Engine (巨量引擎) ads (广告计划) for an advertiser, optionally filtered by ID, campaign, name, status or creation/modification time, with pagination.",
+		Annotations: readTool("List ads"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in listAdsInput) (*mcp.CallToolResult, *listAdsOutput, error) {
+		if in.AdvertiserID == 0 {
+			return nil, nil, fmt.Errorf("advertiser_id is required")
+		}
+		if err := g.authorize(req, in.AdvertiserID); err != nil {
+			return nil, nil, err
+		}
+		filter := &oceanengine.AdFilter{
+			IDs:        in.AdIDs,
+			Name:       in.Name,
+			Status:     in.Status,
+			CampaignID: in.CampaignID,
+			CreateTime: in.CreateDate,
+			ModifyTime: in.ModifyHour,
+		}
+		if in.AllPages {
+			limit := maxItems(in.MaxItems)
+			list, truncated, err := collect(client.AllAds(ctx, in.AdvertiserID, filter, limit+1), limit)
+			if err != nil {
+				return nil, nil, toolError(err)
+			}
+			return nil, &listAdsOutput{
+				AdList:    oceanengine.AdList{List: list, PageInfo: allPagesInfo(len(list))},
+				Truncated: truncated,
+			}, nil
+		}
+		res, err := client.ListAds(ctx, in.AdvertiserID, filter, in.Page, in.PageSize)
+		if err != nil {
+			return nil, nil, toolError(err)
+		}
+		return nil, &listAdsOutput{AdList: *res}, nil
+	})
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        "oceanengine_list_creatives",
+		Description: "List Ocean Engine (巨量引擎) creatives (创意) for an advertiser — title, image/video IDs, status and audit rejection reason — optionally filtered by ad, ID, campaign or status, with pagination.",
+		Annotations: readTool("List creatives"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in listCreativesInput) (*mcp.CallToolResult, *listCreativesOutput, error) {
+		if in.AdvertiserID == 0 {
+			return nil, nil, fmt.Errorf("advertiser_id is required")
+		}
+		if err := g.authorize(req, in.AdvertiserID); err != nil {
+			return nil, nil, err
+		}
+		filter := &oceanengine.CreativeFilter{
+			IDs:        in.CreativeIDs,
+			AdID:       in.AdID,
+			CampaignID: in.CampaignID,
+			Status:     in.Status,
+		}
+		if in.AllPages {
+			limit := maxItems(in.MaxItems)
+			list, truncated, err := collect(client.AllCreatives(ctx, in.AdvertiserID, filter, limit+1), limit)
+			if err != nil {
+				return nil, nil, toolError(err)
+			}
+			return nil, &listCreativesOutput{
+				CreativeList: oceanengine.CreativeList{List: list, PageInfo: allPagesInfo(len(list))},
+				Truncated:    truncated,
+			}, nil
+		}
+		res, err := client.ListCreatives(ctx, in.AdvertiserID, filter, in.Page, in.PageSize)
+		if err != nil {
+			return nil, nil, toolError(err)
+		}
+		return nil, &listCreativesOutput{CreativeList: *res}, nil
+	})
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        "oceanengine_get_creative_details",
+		Description: "Get the full creative (创意) details of one Ocean Engine (巨量引擎) ad: its placements and source, and every creative's title, image/video IDs and status.",
+		Annotations: readTool("Get creative details"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in getCreativeDetailsInput) (*mcp.CallToolResult, *getCreativeDetailsOutput, error) {
+		if in.AdvertiserID == 0 || in.AdID == 0 {
+			return nil, nil, fmt.Errorf("advertiser_id and ad_id are required")
+		}
+		if err := g.authorize(req, in.AdvertiserID); err != nil {
+			return nil, nil, err
+		}
+		res, err := client.GetCreativeDetail(ctx, in.AdvertiserID, in.AdID)
+		if err != nil {
+			return nil, nil, toolError(err)
+		}
+		return nil, &getCreativeDetailsOutput{CreativeDetail: *res}, nil
+	})
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        "oceanengine_list_projects",
+		Description: "List Ocean Engine (巨量引擎) projects (项目) for an advertiser on the upgraded 巨量广告 model, optionally filtered by ID, name, status, landing type or delivery mode, with pagination. Accounts on this model have projects and promotions instead of campaigns and ads.",
+		Annotations: readTool("List projects"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in listProjectsInput) (*mcp.CallToolResult, *listProjectsOutput, error) {
 		if in.AdvertiserID == 0 {
 			return nil, nil, fmt.Errorf("advertiser_id is required")
 		}
-		res, err := client.ListAds(ctx, in.AdvertiserID, in.Page, in.PageSize)
+		if err := g.authorize(req, in.AdvertiserID); err != nil {
+			return nil, nil, err
+		}
+		filter := &oceanengine.ProjectFilter{
+			IDs:          in.ProjectIDs,
+			Name:         in.Name,
+			StatusFirst:  in.StatusFirst,
+			LandingType:  in.LandingType,
+			DeliveryMode: in.DeliveryMode,
+		}
+		if in.AllPages {
+			limit := maxItems(in.MaxItems)
+			list, truncated, err := collect(client.AllProjects(ctx, in.AdvertiserID, filter, limit+1), limit)
+			if err != nil {
+				return nil, nil, toolError(err)
+			}
+			return nil, &listProjectsOutput{
+				ProjectList: oceanengine.ProjectList{List: list, PageInfo: allPagesInfo(len(list))},
+				Truncated:   truncated,
+			}, nil
+		}
+		res, err := client.ListProjects(ctx, in.AdvertiserID, filter, in.Page, in.PageSize)
 		if err != nil {
+			return nil, nil, toolError(err)
+		}
+		return nil, &listProjectsOutput{ProjectList: *res}, nil
+	})
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        "oceanengine_list_promotions",
+		Description: "List Ocean Engine (巨量引擎) promotions (广告/单元) for an advertiser on the upgraded 巨量广告 model, optionally filtered by ID, project, name or status, with pagination.",
+		Annotations: readTool("List promotions"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in listPromotionsInput) (*mcp.CallToolResult, *listPromotionsOutput, error) {
+		if in.AdvertiserID == 0 {
+			return nil, nil, fmt.Errorf("advertiser_id is required")
+		}
+		if err := g.authorize(req, in.AdvertiserID); err != nil {
 			return nil, nil, err
 		}
-		return nil, res, nil
+		filter := &oceanengine.PromotionFilter{
+			IDs:         in.PromotionIDs,
+			ProjectID:   in.ProjectID,
+			Name:        in.Name,
+			StatusFirst: in.StatusFirst,
+		}
+		if in.AllPages {
+			limit := maxItems(in.MaxItems)
+			list, truncated, err := collect(client.AllPromotions(ctx, in.AdvertiserID, filter, limit+1), limit)
+			if err != nil {
+				return nil, nil, toolError(err)
+			}
+			return nil, &listPromotionsOutput{
+				PromotionList: oceanengine.PromotionList{List: list, PageInfo: allPagesInfo(len(list))},
+				Truncated:     truncated,
+			}, nil
+		}
+		res, err := client.ListPromotions(ctx, in.AdvertiserID, filter, in.Page, in.PageSize)
+		if err != nil {
+			return nil, nil, toolError(err)
+		}
+		return nil, &listPromotionsOutput{PromotionList: *res}, nil
 	})
 
 	mcp.AddTool(srv, &mcp.Tool{
 		Name:        "oceanengine_get_report",
-		Description: "Get an Ocean Engine (巨量引擎) ad performance report for a date range, grouped by the given dimensions.",
-	}, func(ctx context.Context, _ *mcp.CallToolRequest, in getReportInput) (*mcp.CallToolResult, *oceanengine.ReportResult, error) {
+		Description: "Get an Ocean Engine (巨量引擎) performance report for a date range at the advertiser, campaign, ad or creative level, grouped by the given dimensions.",
+		Annotations: readTool("Get report"),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in getReportInput) (*mcp.CallToolResult, *getReportOutput, error) {
 		if in.AdvertiserID == 0 {
 			return nil, nil, fmt.Errorf("advertiser_id is required")
 		}
+		if err := g.authorize(req, in.AdvertiserID); err != nil {
+			return nil, nil, err
+		}
 		if in.StartDate == "" || in.EndDate == "" {
 			return nil, nil, fmt.Errorf("start_date and end_date are required")
 		}
-		res, err := client.GetReport(ctx, oceanengine.ReportRequest{
+		level, err := reportLevel(in.Level, in.GroupBy)
+		if err != nil {
+			return nil, nil, err
+		}
+		rr := oceanengine.ReportRequest{
 			AdvertiserID: in.AdvertiserID,
+			Level:        level,
 			StartDate:    in.StartDate,
 			EndDate:      in.EndDate,
 			GroupBy:      in.GroupBy,
 			Fields:       in.Fields,
 			Page:         in.Page,
 			PageSize:     in.PageSize,
-		})
+		}
+		if in.AllPages {
+			limit := maxItems(in.MaxItems)
+			rows, truncated, err := collect(client.AllReportRows(ctx, rr, limit+1), limit)
+			if err != nil {
+				return nil, nil, toolError(err)
+			}
+			return nil, &getReportOutput{
+				ReportResult: oceanengine.ReportResult{List: rows, PageInfo: allPagesInfo(len(rows))},
+				Truncated:    truncated,
+			}, nil
+		}
+		res, err := client.GetReport(ctx, rr)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, toolError(err)
 		}
-		return nil, res, nil
+		return nil, &getReportOutput{ReportResult: *res}, nil
 	})
 }
 
-// ---------------------------------------------------------------------------
-// Write tools (only registered when EnableWrites is true)
-// ---------------------------------------------------------------------------
+// reportLevel parses a level argument and checks that the report endpoint of
+// that level accepts every group_by dimension.
+func reportLevel(arg string, groupBy []string) (oceanengine.ReportLevel, error) {
+	level := oceanengine.ReportLevel(arg)
+	if arg == "" {
+		level = oceanengine.ReportAd
+	}
+	if !slices.Contains(oceanengine.ReportLevels, level) {
+		return "", fmt.Errorf("level must be one of advertiser, campaign, ad, creative")
+	}
+	allowed := level.GroupBy()
+	for _, dim := range groupBy {
+		if !slices.Contains(allowed, dim) {
+			return "", fmt.Errorf("%s reports cannot be grouped by %s; use %s", level, dim, strings.Join(allowed, ", "))
+		}
+	}
+	return level, nil
+}
 
-type updateStatusInput struct {
-	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
-	CampaignIDs  []int64 `json:"campaign_ids" jsonschema:"campaign IDs to update"`
-	OptStatus    string  `json:"opt_status" jsonschema:"one of: enable, disable, delete"`
+// defaultMaxItems caps all_pages results when the agent gives no max_items,
+// keeping a single tool result to a size an agent can reasonably read.
+const defaultMaxItems = 1000
+
+func maxItems(n int) int {
+	if n <= 0 {
+		return defaultMaxItems
+	}
+	return n
 }
 
-type updateBudgetInput struct {
-	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
-	CampaignID   int64   `json:"campaign_id" jsonschema:"campaign ID to update"`
-	Budget       float64 `json:"budget" jsonschema:"new budget amount"`
-	BudgetMode   string  `json:"budget_mode,omitempty" jsonschema:"budget mode; defaults to BUDGET_MODE_DAY"`
+// collect drains seq, keeping at most limit items, and reports whether seq
+// had more. seq should be capped at limit+1 so no further pages are fetched.
+func collect[T any](seq iter.Seq2[T, error], limit int) ([]T, bool, error) {
+	items := []T{}
+	for item, err := range seq {
+		if err != nil {
+			return nil, false, err
+		}
+		if len(items) == limit {
+			return items, true, nil
+		}
+		items = append(items, item)
+	}
+	return items, false, nil
 }
 
-type okOutput struct {
-	OK bool `json:"ok"`
+// allPagesInfo describes a result assembled from every page as a single page.
+func allPagesInfo(n int) oceanengine.PageInfo {
+	return oceanengine.PageInfo{Page: 1, PageSize: n, TotalNumber: n, TotalPage: 1}
 }
 
-func registerWriteTools(srv *mcp.Server, client *oceanengine.Client) {
-	mcp.AddTool(srv, &mcp.Tool{
-		Name:        "oceanengine_update_campaign_status",
-		Description: "WRITE: enable, disable or delete Ocean Engine (巨量引擎) campaigns. This mutates the live account.",
-	}, func(ctx context.Context, _ *mcp.CallToolRequest, in updateStatusInput) (*mcp.CallToolResult, okOutput, error) {
-		switch in.OptStatus {
-		case "enable", "disable", "delete":
-		default:
-			return nil, okOutput{}, fmt.Errorf("opt_status must be one of enable, disable, delete")
+// listAuthorized lists the authorized accounts and, if requested, the child
+// accounts under each majordomo and agency account.
+func listAuthorized(ctx context.Context, client *oceanengine.Client, includeChildren bool) ([]authorizedAdvertiser, error) {
+	auth, err := client.ListAuthorizedAdvertisers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reg := client.Registry()
+	var out []authorizedAdvertiser
+	for _, a := range auth {
+		if reg != nil {
+			// Route later requests for newly authorized accounts to the
+			// token that can reach them.
+			_ = reg.AddAdvertisers(a.User, a.AdvertiserID)
 		}
-		if in.AdvertiserID == 0 || len(in.CampaignIDs) == 0 {
-			return nil, okOutput{}, fmt.Errorf("advertiser_id and campaign_ids are required")
+		out = append(out, authorizedAdvertiser{
+			AdvertiserID:   a.AdvertiserID,
+			AdvertiserName: a.AdvertiserName,
+			AccountRole:    a.AccountRole,
+			User:           a.User,
+		})
+		if !includeChildren {
+			continue
 		}
-		if err := client.UpdateCampaignStatus(ctx, in.AdvertiserID, in.CampaignIDs, in.OptStatus); err != nil {
-			return nil, okOutput{}, err
+		var children []oceanengine.ChildAdvertiser
+		switch a.AccountRole {
+		case oceanengine.RoleCustomerAdmin:
+			if children, err = majordomoChildren(ctx, client, a.AdvertiserID); err != nil {
+				return nil, err
+			}
+		case oceanengine.RoleAgent, oceanengine.RoleChildAgent:
+			if children, err = agentChildren(ctx, client, a.AdvertiserID); err != nil {
+				return nil, err
+			}
 		}
-		return nil, okOutput{OK: true}, nil
-	})
-
-	mcp.AddTool(srv, &mcp.Tool{
-		Name:        "oceanengine_update_campaign_budget",
-		Description: "WRITE: set a new budget for an Ocean Engine (巨量引擎) campaign. This mutates the live account.",
-	}, func(ctx context.Context, _ *mcp.CallToolRequest, in updateBudgetInput) (*mcp.CallToolResult, okOutput, error) {
-		if in.AdvertiserID == 0 || in.CampaignID == 0 {
-			return nil, okOutput{}, fmt.Errorf("advertiser_id and campaign_id are required")
+		for _, c := range children {
+			out = append(out, authorizedAdvertiser{
+				AdvertiserID:   c.AdvertiserID,
+				AdvertiserName: c.AdvertiserName,
+				AccountRole:    oceanengine.RoleAdvertiser,
+				ParentID:       a.AdvertiserID,
+				User:           a.User,
+			})
 		}
-		mode := in.BudgetMode
-		if mode == "" {
-			mode = "BUDGET_MODE_DAY"
+	}
+	return out, nil
+}
+
+// adoptChildren maps child advertisers to the registry account of their
+// parent, if the client uses a registry, so later requests for them use the
+// token that manages them.
+func adoptChildren(client *oceanengine.Client, parentID int64, childIDs []int64) {
+	if reg := client.Registry(); reg != nil {
+		reg.AddChildAdvertisers(parentID, childIDs...)
+	}
+}
+
+// majordomoChildren lists a majordomo account's advertisers.
+func majordomoChildren(ctx context.Context, client *oceanengine.Client, majordomoID int64) ([]oceanengine.ChildAdvertiser, error) {
+	children, err := client.ListMajordomoAdvertisers(ctx, majordomoID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(children))
+	for i, c := range children {
+		ids[i] = c.AdvertiserID
+	}
+	adoptChildren(client, majordomoID, ids)
+	return children, nil
+}
+
+// agentChildren lists an agency's advertisers; the agency endpoint returns
+// bare IDs, so names are filled in from advertiser info, 100 at a time.
+func agentChildren(ctx context.Context, client *oceanengine.Client, agentID int64) ([]oceanengine.ChildAdvertiser, error) {
+	ids, err := client.ListAgentAdvertisers(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	// Adopted first: the info lookup below is routed by their IDs.
+	adoptChildren(client, agentID, ids)
+	out := make([]oceanengine.ChildAdvertiser, 0, len(ids))
+	for chunk := range slices.Chunk(ids, 100) {
+		info, err := client.GetAdvertiserInfo(ctx, chunk, []string{"id", "name"})
+		if err != nil {
+			return nil, err
 		}
-		if err := client.UpdateCampaignBudget(ctx, in.AdvertiserID, in.CampaignID, in.Budget, mode); err != nil {
-			return nil, okOutput{}, err
+		for _, a := range info {
+			out = append(out, oceanengine.ChildAdvertiser{AdvertiserID: a.ID, AdvertiserName: a.Name})
 		}
-		return nil, okOutput{OK: true}, nil
-	})
+	}
+	return out, nil
 }
@@ -2,8 +2,10 @@ package mcpserver
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -14,13 +16,25 @@ import (
 // connect wires an in-memory MCP client to a server backed by the given
 // Ocean Engine HTTP test server.
 func connect(t *testing.T, apiURL string, cfg Config) *mcp.ClientSession {
+	t.Helper()
+	return connectClient(t, oceanengine.NewClient("tok", oceanengine.WithBaseURL(apiURL)), cfg)
+}
+
+// connectClient wires an in-memory MCP client to a server backed by client.
+func connectClient(t *testing.T, client *oceanengine.Client, cfg Config) *mcp.ClientSession {
+	t.Helper()
+	return connectWithOptions(t, client, cfg, nil)
+}
+
+// connectWithOptions is connectClient with MCP client options, e.g. to handle
+// elicitation.
+func connectWithOptions(t *testing.T, client *oceanengine.Client, cfg Config, opts *mcp.ClientOptions) *mcp.ClientSession {
 	t.Helper()
 	ctx := context.Background()
 
-	client := oceanengine.NewClient("tok", oceanengine.WithBaseURL(apiURL))
 	srv := New(client, cfg)
 
-	c := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v0"}, nil)
+	c := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v0"}, opts)
 	st, ct := mcp.NewInMemoryTransports()
 	if _, err := srv.Connect(ctx, st, nil); err != nil {
 		t.Fatalf("server connect: %v", err)
@@ -51,8 +65,13 @@ func TestReadOnlyByDefault(t *testing.T) {
 
 	for _, want := range []string{
 		"oceanengine_get_advertiser_info",
+		"oceanengine_list_authorized_advertisers",
 		"oceanengine_list_campaigns",
 		"oceanengine_list_ads",
+		"oceanengine_list_projects",
+		"oceanengine_list_promotions",
+		"oceanengine_list_creatives",
+		"oceanengine_get_creative_details",
 		"oceanengine_get_report",
 	} {
 		if !names[want] {
@@ -107,3 +126,273 @@ func TestCallToolValidationError(t *testing.T) {
 		t.Fatal("expected IsError for empty advertiser_ids")
 	}
 }
+
+func TestCallToolActionableError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"code":40102,"message":"access token expired","request_id":"req-e"}`))
+	}))
+	defer ts.Close()
+
+	cs := connect(t, ts.URL, Config{})
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_list_campaigns",
+		Arguments: map[string]any{"advertiser_id": 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsError || len(res.Content) == 0 {
+		t.Fatalf("expected error result, got %+v", res)
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "re-authorize") || !strings.Contains(text, "req-e") {
+		t.Fatalf("error %q should tell the user to re-authorize and keep the request_id", text)
+	}
+}
+
+func TestListAuthorizedAdvertisersWithChildren(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open_api/oauth2/advertiser/get/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[
+				{"advertiser_id":1,"advertiser_name":"own","account_role":"ADVERTISER"},
+				{"advertiser_id":2,"advertiser_name":"mgr","account_role":"CUSTOMER_ADMIN"}]}}`))
+		case "/open_api/2/majordomo/advertiser/select/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"advertiser_id":21,"advertiser_name":"child"}]}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client := oceanengine.NewClient("tok", oceanengine.WithBaseURL(ts.URL), oceanengine.WithAppCredentials(1, "s"))
+	cs := connectClient(t, client, Config{})
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_list_authorized_advertisers",
+		Arguments: map[string]any{"include_children": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("tool returned error result: %+v", res.Content)
+	}
+	var out listAuthorizedOutput
+	decodeStructured(t, res, &out)
+	if len(out.Advertisers) != 3 || out.Advertisers[2].AdvertiserID != 21 || out.Advertisers[2].ParentID != 2 {
+		t.Fatalf("unexpected advertisers: %+v", out.Advertisers)
+	}
+}
+
+func TestListAuthorizedAdvertisersAdoptsChildren(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open_api/oauth2/advertiser/get/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"advertiser_id":10,"account_role":"AGENT"}]}}`))
+		case "/open_api/2/agent/advertiser/select/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[11],"page_info":{"page":1,"total_page":1}}}`))
+		case "/open_api/2/advertiser/info/":
+			_, _ = w.Write([]byte(`{"code":0,"data":[{"id":11,"name":"child"}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	reg := oceanengine.NewTokenRegistry()
+	if err := reg.Register(oceanengine.Account{User: "agency", AdvertiserIDs: []int64{10}, Tokens: oceanengine.StaticToken("t")}); err != nil {
+		t.Fatal(err)
+	}
+	client := oceanengine.NewClient("", oceanengine.WithBaseURL(ts.URL), oceanengine.WithTokenProvider(reg), oceanengine.WithAppCredentials(1, "s"))
+	cs := connectClient(t, client, Config{})
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_list_authorized_advertisers",
+		Arguments: map[string]any{"include_children": true},
+	})
+	if err != nil || res.IsError {
+		t.Fatalf("call: %v %+v", err, res)
+	}
+	if tok, err := reg.TokenFor(context.Background(), 11); err != nil || tok != "t" {
+		t.Fatalf("child 11 should use the agency token, got %q, %v", tok, err)
+	}
+}
+
+// decodeStructured unmarshals a tool result's structured content into out.
+func decodeStructured(t *testing.T, res *mcp.CallToolResult, out any) {
+	t.Helper()
+	raw, err := json.Marshal(res.StructuredContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListCampaignsAllPages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"id":1},{"id":2}],"page_info":{"page":1,"total_page":2}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"id":3}],"page_info":{"page":2,"total_page":2}}}`))
+	}))
+	defer ts.Close()
+
+	cs := connect(t, ts.URL, Config{})
+	for _, tc := range []struct {
+		maxItems      int
+		wantLen       int
+		wantTruncated bool
+	}{{0, 3, false}, {2, 2, true}, {3, 3, false}} {
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "oceanengine_list_campaigns",
+			Arguments: map[string]any{"advertiser_id": 1, "all_pages": true, "max_items": tc.maxItems},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out listCampaignsOutput
+		decodeStructured(t, res, &out)
+		if len(out.List) != tc.wantLen || out.Truncated != tc.wantTruncated {
+			t.Errorf("max_items=%d: got %d items, truncated=%v", tc.maxItems, len(out.List), out.Truncated)
+		}
+	}
+}
+
+func TestListAdsFilterArguments(t *testing.T) {
+	var filtering string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filtering = r.URL.Query().Get("filtering")
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[]}}`))
+	}))
+	defer ts.Close()
+
+	cs := connect(t, ts.URL, Config{})
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_list_ads",
+		Arguments: map[string]any{"advertiser_id": 1, "campaign_id": 7, "status": "AD_STATUS_DISABLE"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("tool returned error result: %+v", res.Content)
+	}
+	if filtering != `{"status":"AD_STATUS_DISABLE","campaign_id":7}` {
+		t.Fatalf("filtering = %s", filtering)
+	}
+}
+
+func TestListPromotionsFilterArguments(t *testing.T) {
+	var path, filtering string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, filtering = r.URL.Path, r.URL.Query().Get("filtering")
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"promotion_id":5,"promotion_name":"U","project_id":11}],"page_info":{"page":1,"total_page":1}}}`))
+	}))
+	defer ts.Close()
+
+	cs := connect(t, ts.URL, Config{})
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_list_promotions",
+		Arguments: map[string]any{"advertiser_id": 1, "project_id": 11, "status_first": "PROMOTION_STATUS_DISABLE"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out listPromotionsOutput
+	decodeStructured(t, res, &out)
+	if path != "/open_api/v3.0/promotion/list/" || filtering != `{"project_id":11,"status_first":"PROMOTION_STATUS_DISABLE"}` {
+		t.Fatalf("path = %s, filtering = %s", path, filtering)
+	}
+	if len(out.List) != 1 || out.List[0].ID != 5 {
+		t.Fatalf("out = %+v", out)
+	}
+}
+
+func TestCreativeTools(t *testing.T) {
+	var filtering string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open_api/2/creative/get/":
+			filtering = r.URL.Query().Get("filtering")
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"creative_id":70,"ad_id":7,"title":"T","status":"CREATIVE_STATUS_AUDIT_DENY","audit_reject_reason":"misleading claim"}],"page_info":{"page":1,"total_page":1}}}`))
+		case "/open_api/2/creative/read_v2/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"ad_id":7,"advertiser_id":1,"creatives":[{"creative_id":70,"title":"T","video_id":"v0200"}]}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	cs := connect(t, ts.URL, Config{})
+
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_list_creatives",
+		Arguments: map[string]any{"advertiser_id": 1, "ad_id": 7},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list listCreativesOutput
+	decodeStructured(t, res, &list)
+	if filtering != `{"ad_id":7}` {
+		t.Fatalf("filtering = %s", filtering)
+	}
+	if len(list.List) != 1 || list.List[0].AuditRejectReason != "misleading claim" {
+		t.Fatalf("list = %+v", list)
+	}
+
+	res, err = cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_get_creative_details",
+		Arguments: map[string]any{"advertiser_id": 1, "ad_id": 7},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var detail getCreativeDetailsOutput
+	decodeStructured(t, res, &detail)
+	if detail.AdID != 7 || len(detail.Creatives) != 1 || detail.Creatives[0].VideoID != "v0200" {
+		t.Fatalf("detail = %+v", detail)
+	}
+}
+
+func TestGetReportLevel(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"campaign_id":7,"cost":12.5}],"page_info":{"page":1,"total_page":1}}}`))
+	}))
+	defer ts.Close()
+	cs := connect(t, ts.URL, Config{})
+
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "oceanengine_get_report",
+		Arguments: map[string]any{"advertiser_id": 1, "level": "campaign", "start_date": "2026-10-01", "end_date": "2026-10-07",
+			"group_by": []string{"STAT_GROUP_BY_FIELD_ID"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out getReportOutput
+	decodeStructured(t, res, &out)
+	if len(paths) != 1 || paths[0] != "/open_api/2/report/campaign/get/" || len(out.List) != 1 {
+		t.Fatalf("paths = %v, out = %+v", paths, out)
+	}
+
+	for _, args := range []map[string]any{
+		{"level": "keyword"},
+		{"level": "advertiser", "group_by": []string{"STAT_GROUP_BY_FIELD_ID"}},
+	} {
+		args["advertiser_id"], args["start_date"], args["end_date"] = 1, "2026-10-01", "2026-10-07"
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "oceanengine_get_report", Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.IsError {
+			t.Errorf("%v: want a tool error", args)
+		}
+	}
+	if len(paths) != 1 {
+		t.Fatalf("invalid reports reached Ocean Engine: %v", paths)
+	}
+}
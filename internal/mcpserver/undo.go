@@ -0,0 +1,215 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+type undoInput struct {
+	ChangeID string `json:"change_id" jsonschema:"the audit_id of the status or budget change to revert"`
+	DryRun   bool   `json:"dry_run,omitempty" jsonschema:"validate and preview the revert without applying it"`
+}
+
+// registerUndoTool adds oceanengine_undo_change. Undo works from the state
+// recorded in the audit log, so it needs an AuditSink.
+func registerUndoTool(srv *mcp.Server, w *writeTools, mode string) {
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolUndoChange,
+		Description: mode + ": revert an Ocean Engine (巨量引擎) campaign status or budget change made through this server, by its audit_id. Refuses if the campaigns have been modified since. The user is asked to confirm first.",
+		Annotations: w.writeTool("Undo change", false, false),
+	}, audited(w, func(undoInput) int64 { return 0 }, w.undo))
+}
+
+// optStatusOf reduces a campaign state to "enable" or "disable", the
+// opt_status that restores it, or "" if it is neither.
+func optStatusOf(s State) string {
+	for _, v := range []string{s.OptStatus, s.Status} {
+		switch {
+		case strings.HasSuffix(v, "DISABLE"):
+			return "disable"
+		case strings.HasSuffix(v, "ENABLE"):
+			return "enable"
+		}
+	}
+	return ""
+}
+
+// sameState reports whether a campaign is still in the state a change left it
+// in, comparing only what the write tools change.
+func sameState(cur, after State) bool {
+	return optStatusOf(cur) == optStatusOf(after) &&
+		cur.Budget == after.Budget && cur.BudgetMode == after.BudgetMode
+}
+
+func (w *writeTools) undo(ctx context.Context, req *mcp.CallToolRequest, in undoInput, e *AuditEntry) (*writeOutput, error) {
+	if in.ChangeID == "" {
+		return nil, fmt.Errorf("change_id is required")
+	}
+	e.Undoes = in.ChangeID
+	if err := w.guard.authorize(req); err != nil {
+		return nil, err
+	}
+	found, err := w.audit.Entries(ctx, AuditQuery{ID: in.ChangeID, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	// A change to an advertiser the caller may not touch is reported as
+	// missing, so that change IDs cannot be probed.
+	if len(found) == 0 || w.guard.authorize(req, found[0].AdvertiserID) != nil {
+		return nil, fmt.Errorf("no recorded change with ID %q; use oceanengine_get_audit_log to find it", in.ChangeID)
+	}
+	orig := found[0]
+	e.AdvertiserID = orig.AdvertiserID
+	if err := w.checkUndoable(ctx, &orig); err != nil {
+		return nil, err
+	}
+
+	applied := appliedChanges(&orig)
+	if err := w.policy.checkCount("campaigns", len(applied)); err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(applied))
+	for i, ch := range applied {
+		ids[i] = ch.CampaignID
+	}
+	cur, err := currentCampaigns(ctx, w.client, orig.AdvertiserID, ids)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]CampaignChange, len(applied))
+	for i, ch := range applied {
+		now := stateOf(cur[ch.CampaignID])
+		if !sameState(now, ch.After) {
+			return nil, fmt.Errorf("refused: campaign %s has been modified since change %s (now %s, %s; the change left it %s, %s); revert it by hand if still needed",
+				campaignLabel(ch), orig.ID, now.Status, budgetString(now), ch.After.Status, budgetString(ch.After))
+		}
+		if orig.Tool == toolUpdateBudget {
+			if err := w.policy.checkBudget(campaignLabel(ch), now, ch.Before.Budget, ch.Before.BudgetMode); err != nil {
+				return nil, err
+			}
+		}
+		changes[i] = CampaignChange{CampaignID: ch.CampaignID, CampaignName: ch.CampaignName, Before: now, After: ch.Before}
+	}
+	e.Changes = changes
+
+	summary := fmt.Sprintf("Undo change %s in account %d: %s", orig.ID, orig.AdvertiserID, undoSummary(&orig, changes))
+	return w.commit(ctx, req, e, &writeOutput{Changes: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.revert(ctx, &orig, changes)
+	})
+}
+
+// appliedChanges returns the changes of orig that Ocean Engine applied: all
+// of them, unless it reported some items of the batch as failed.
+func appliedChanges(orig *AuditEntry) []CampaignChange {
+	if orig.Result == nil {
+		return orig.Changes
+	}
+	return slices.DeleteFunc(slices.Clone(orig.Changes), func(ch CampaignChange) bool {
+		return !slices.Contains(orig.Result.Succeeded, ch.CampaignID)
+	})
+}
+
+// checkUndoable refuses changes that cannot or must not be reverted.
+func (w *writeTools) checkUndoable(ctx context.Context, orig *AuditEntry) error {
+	switch orig.Tool {
+	case toolUpdateStatus, toolUpdateBudget:
+	case toolUndoChange:
+		return fmt.Errorf("change %s is itself an undo; make the change again directly instead", orig.ID)
+	default:
+		return fmt.Errorf("change %s (%s) cannot be undone", orig.ID, orig.Tool)
+	}
+	if orig.Outcome != OutcomeApplied && orig.Outcome != OutcomePartial {
+		return fmt.Errorf("change %s was not applied (outcome %q); there is nothing to undo", orig.ID, orig.Outcome)
+	}
+	if len(appliedChanges(orig)) == 0 {
+		return fmt.Errorf("change %s has no recorded prior state", orig.ID)
+	}
+	if orig.Tool == toolUpdateStatus && orig.Arguments["opt_status"] == "delete" {
+		return fmt.Errorf("change %s deleted campaigns; Ocean Engine deletions cannot be undone", orig.ID)
+	}
+	undos, err := w.audit.Entries(ctx, AuditQuery{AdvertiserID: orig.AdvertiserID, Tool: toolUndoChange})
+	if err != nil {
+		return err
+	}
+	if i := slices.IndexFunc(undos, func(u AuditEntry) bool {
+		return u.Undoes == orig.ID && (u.Outcome == OutcomeApplied || u.Outcome == OutcomePartial)
+	}); i >= 0 {
+		return fmt.Errorf("change %s was already undone by %s", orig.ID, undos[i].ID)
+	}
+	for _, ch := range orig.Changes {
+		if orig.Tool == toolUpdateStatus && optStatusOf(ch.Before) == "" {
+			return fmt.Errorf("campaign %s was %s before change %s, which cannot be restored by enabling or disabling it",
+				campaignLabel(ch), ch.Before.Status, orig.ID)
+		}
+	}
+	return nil
+}
+
+// revert applies the inverse of orig.
+func (w *writeTools) revert(ctx context.Context, orig *AuditEntry, changes []CampaignChange) (*oceanengine.BatchResult, error) {
+	total := &oceanengine.BatchResult{Succeeded: []int64{}}
+	add := func(res *oceanengine.BatchResult) {
+		total.Succeeded = append(total.Succeeded, res.Succeeded...)
+		total.Failed = append(total.Failed, res.Failed...)
+	}
+	if orig.Tool == toolUpdateBudget {
+		for _, ch := range changes {
+			res, err := w.client.UpdateCampaignBudget(ctx, orig.AdvertiserID, ch.CampaignID, ch.After.Budget, ch.After.BudgetMode)
+			if err != nil {
+				return nil, err
+			}
+			add(res)
+		}
+		return total, nil
+	}
+	// One status call per target status, in a stable order.
+	byStatus := map[string][]int64{}
+	for _, ch := range changes {
+		opt := optStatusOf(ch.After)
+		byStatus[opt] = append(byStatus[opt], ch.CampaignID)
+	}
+	for _, opt := range []string{"disable", "enable"} {
+		if ids := byStatus[opt]; len(ids) > 0 {
+			res, err := w.client.UpdateCampaignStatus(ctx, orig.AdvertiserID, ids, opt)
+			if err != nil {
+				return nil, err
+			}
+			add(res)
+		}
+	}
+	return total, nil
+}
+
+func undoSummary(orig *AuditEntry, changes []CampaignChange) string {
+	if orig.Tool == toolUpdateBudget {
+		parts := make([]string, len(changes))
+		for i, ch := range changes {
+			parts[i] = fmt.Sprintf("restore the budget of %s from %s to %s", campaignLabel(ch), budgetString(ch.Before), budgetString(ch.After))
+		}
+		return strings.Join(parts, "; ")
+	}
+	var parts []string
+	for _, opt := range []string{"disable", "enable"} {
+		var sub []CampaignChange
+		for _, ch := range changes {
+			if optStatusOf(ch.After) == opt {
+				sub = append(sub, ch)
+			}
+		}
+		if len(sub) == 0 {
+			continue
+		}
+		verb := "disable"
+		if opt == "enable" {
+			verb = "re-enable"
+		}
+		parts = append(parts, verb+" "+names(campaignLabels(sub)))
+	}
+	return strings.Join(parts, "; ")
+}
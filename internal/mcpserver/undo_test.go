@@ -0,0 +1,229 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// fakeAccount is a stateful stand-in for the campaign endpoints.
+type fakeAccount struct {
+	mu        sync.Mutex
+	campaigns map[int64]*oceanengine.Campaign
+}
+
+func (f *fakeAccount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/open_api/2/campaign/get/":
+		var filter struct {
+			IDs []int64 `json:"ids"`
+		}
+		_ = json.Unmarshal([]byte(r.URL.Query().Get("filtering")), &filter)
+		list := []oceanengine.Campaign{}
+		for _, id := range filter.IDs {
+			if c, ok := f.campaigns[id]; ok {
+				list = append(list, *c)
+			}
+		}
+		raw, _ := json.Marshal(list)
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":` + string(raw) + `,"page_info":{"page":1,"total_page":1}}}`))
+	case "/open_api/2/campaign/update/status/":
+		var body struct {
+			CampaignIDs []int64 `json:"campaign_ids"`
+			OptStatus   string  `json:"opt_status"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, id := range body.CampaignIDs {
+			f.campaigns[id].Status = optStatusResult[body.OptStatus]
+			f.campaigns[id].OptStatus = optStatusResult[body.OptStatus]
+		}
+		_, _ = w.Write([]byte(`{"code":0,"request_id":"req-s","data":{}}`))
+	case "/open_api/2/campaign/update/budget/":
+		var body struct {
+			Data []struct {
+				CampaignID int64   `json:"campaign_id"`
+				Budget     float64 `json:"budget"`
+				BudgetMode string  `json:"budget_mode"`
+			} `json:"data"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, d := range body.Data {
+			f.campaigns[d.CampaignID].Budget, f.campaigns[d.CampaignID].BudgetMode = d.Budget, d.BudgetMode
+		}
+		_, _ = w.Write([]byte(`{"code":0,"request_id":"req-b","data":{}}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeAccount) get(id int64) oceanengine.Campaign {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.campaigns[id]
+}
+
+// undoFixture serves a fake account and an audit log through a write-enabled
+// server configured by cfg.
+func undoFixture(t *testing.T, cfg Config) (*fakeAccount, func(string, map[string]any) *mcp.CallToolResult) {
+	t.Helper()
+	acct := &fakeAccount{campaigns: map[int64]*oceanengine.Campaign{
+		7: {ID: 7, Name: "A", Status: "CAMPAIGN_STATUS_ENABLE", OptStatus: "CAMPAIGN_STATUS_ENABLE", Budget: 400, BudgetMode: "BUDGET_MODE_DAY"},
+		8: {ID: 8, Name: "B", Status: "CAMPAIGN_STATUS_ENABLE", OptStatus: "CAMPAIGN_STATUS_ENABLE", Budget: 300, BudgetMode: "BUDGET_MODE_DAY"},
+		9: {ID: 9, Name: "C", Status: "CAMPAIGN_STATUS_ADVERTISER_BUDGET_EXCEED", OptStatus: "CAMPAIGN_STATUS_ENABLE", Budget: 200, BudgetMode: "BUDGET_MODE_DAY"},
+	}}
+	ts := httptest.NewServer(acct)
+	t.Cleanup(ts.Close)
+	sink, err := NewFileAuditSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sink.Close() })
+
+	cfg.EnableWrites, cfg.ConfirmFallback, cfg.Audit = true, ConfirmAllow, sink
+	cs := connect(t, ts.URL, cfg)
+	return acct, func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+}
+
+func auditID(t *testing.T, res *mcp.CallToolResult) string {
+	t.Helper()
+	if res.IsError {
+		t.Fatalf("write failed: %+v", res.Content)
+	}
+	var out writeOutput
+	decodeStructured(t, res, &out)
+	return out.AuditID
+}
+
+func errorText(res *mcp.CallToolResult) string {
+	if !res.IsError || len(res.Content) == 0 {
+		return ""
+	}
+	return res.Content[0].(*mcp.TextContent).Text
+}
+
+func TestUndoBudgetChange(t *testing.T) {
+	acct, call := undoFixture(t, Config{})
+
+	id := auditID(t, call(toolUpdateBudget, map[string]any{"advertiser_id": 1, "campaign_id": 7, "budget": 500}))
+	if got := acct.get(7).Budget; got != 500 {
+		t.Fatalf("budget = %g after change", got)
+	}
+
+	res := call(toolUndoChange, map[string]any{"change_id": id})
+	auditID(t, res)
+	if got := acct.get(7).Budget; got != 400 {
+		t.Fatalf("budget = %g after undo, want 400", got)
+	}
+
+	if msg := errorText(call(toolUndoChange, map[string]any{"change_id": id})); !strings.Contains(msg, "already undone") {
+		t.Fatalf("second undo: %q", msg)
+	}
+}
+
+func TestUndoBudgetChangeOfPausedByBudgetCampaign(t *testing.T) {
+	acct, call := undoFixture(t, Config{})
+
+	// The status says why the campaign is not serving; opt_status says it is
+	// switched on. Undo must compare the latter.
+	id := auditID(t, call(toolUpdateBudget, map[string]any{"advertiser_id": 1, "campaign_id": 9, "budget": 600}))
+	auditID(t, call(toolUndoChange, map[string]any{"change_id": id}))
+	if got := acct.get(9).Budget; got != 200 {
+		t.Fatalf("budget = %g after undo, want 200", got)
+	}
+}
+
+func TestUndoStatusChange(t *testing.T) {
+	acct, call := undoFixture(t, Config{})
+
+	id := auditID(t, call(toolUpdateStatus, map[string]any{"advertiser_id": 1, "campaign_ids": []int64{7, 8}, "opt_status": "disable"}))
+	auditID(t, call(toolUndoChange, map[string]any{"change_id": id}))
+	if acct.get(7).Status != "CAMPAIGN_STATUS_ENABLE" || acct.get(8).Status != "CAMPAIGN_STATUS_ENABLE" {
+		t.Fatalf("campaigns not re-enabled: %+v, %+v", acct.get(7), acct.get(8))
+	}
+}
+
+func TestUndoRefusesModifiedCampaign(t *testing.T) {
+	acct, call := undoFixture(t, Config{})
+
+	id := auditID(t, call(toolUpdateStatus, map[string]any{"advertiser_id": 1, "campaign_ids": []int64{8}, "opt_status": "disable"}))
+	// Someone else changes the budget in the meantime.
+	acct.mu.Lock()
+	acct.campaigns[8].Budget = 999
+	acct.mu.Unlock()
+
+	if msg := errorText(call(toolUndoChange, map[string]any{"change_id": id})); !strings.Contains(msg, "modified since") {
+		t.Fatalf("undo after outside change: %q", msg)
+	}
+	if acct.get(8).Status != "CAMPAIGN_STATUS_DISABLE" {
+		t.Fatal("refused undo still changed the campaign")
+	}
+}
+
+func TestUndoRefusesDeleteAndUnknown(t *testing.T) {
+	_, call := undoFixture(t, Config{})
+
+	id := auditID(t, call(toolUpdateStatus, map[string]any{"advertiser_id": 1, "campaign_ids": []int64{7}, "opt_status": "delete"}))
+	if msg := errorText(call(toolUndoChange, map[string]any{"change_id": id})); !strings.Contains(msg, "cannot be undone") {
+		t.Fatalf("undo of delete: %q", msg)
+	}
+	if msg := errorText(call(toolUndoChange, map[string]any{"change_id": "nope"})); !strings.Contains(msg, "no recorded change") {
+		t.Fatalf("undo of unknown ID: %q", msg)
+	}
+}
+
+func TestUndoChecksWritePolicy(t *testing.T) {
+	acct, call := undoFixture(t, Config{Policy: WritePolicy{MaxBudget: 300}})
+
+	// Campaign 7 was above the cap before the change; undoing it would raise
+	// it past the cap again.
+	id := auditID(t, call(toolUpdateBudget, map[string]any{"advertiser_id": 1, "campaign_id": 7, "budget": 250}))
+	if msg := errorText(call(toolUndoChange, map[string]any{"change_id": id})); !strings.Contains(msg, "refused by write policy") {
+		t.Fatalf("undo above the budget cap: %q", msg)
+	}
+	if got := acct.get(7).Budget; got != 250 {
+		t.Fatalf("budget = %g after refused undo, want 250", got)
+	}
+}
+
+func TestUndoHidesOtherAdvertisersChanges(t *testing.T) {
+	ts := httptest.NewServer(&fakeAccount{})
+	defer ts.Close()
+	sink, err := NewFileAuditSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	if err := sink.Append(context.Background(), &AuditEntry{ID: "other", Tool: toolUpdateBudget, AdvertiserID: 1, Outcome: OutcomeApplied}); err != nil {
+		t.Fatal(err)
+	}
+
+	cs := connect(t, ts.URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow, Audit: sink, AllowedAdvertiserIDs: []int64{2}})
+	undo := func(id string) string {
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: toolUndoChange, Arguments: map[string]any{"change_id": id}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.ReplaceAll(errorText(res), id, "ID")
+	}
+	if other, missing := undo("other"), undo("missing"); other != missing || !strings.Contains(other, "no recorded change") {
+		t.Fatalf("undo of another advertiser's change = %q, of a missing one = %q", other, missing)
+	}
+}
@@ -0,0 +1,328 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// ---------------------------------------------------------------------------
+// Project (项目) and promotion (广告/单元) write tools, on the v3.0 API
+// ---------------------------------------------------------------------------
+
+type updateProjectStatusInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	ProjectIDs   []int64 `json:"project_ids" jsonschema:"project IDs to update"`
+	OptStatus    string  `json:"opt_status" jsonschema:"one of: enable, disable"`
+	DryRun       bool    `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+type projectBudget struct {
+	ProjectID int64   `json:"project_id" jsonschema:"project ID"`
+	Budget    float64 `json:"budget" jsonschema:"new budget amount; the project keeps its budget mode"`
+}
+
+type updateProjectBudgetInput struct {
+	AdvertiserID int64           `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	Projects     []projectBudget `json:"projects" jsonschema:"the projects to update, each with its new budget"`
+	DryRun       bool            `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+type updatePromotionStatusInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	PromotionIDs []int64 `json:"promotion_ids" jsonschema:"promotion IDs to update"`
+	OptStatus    string  `json:"opt_status" jsonschema:"one of: enable, disable"`
+	DryRun       bool    `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+type promotionBudget struct {
+	PromotionID int64   `json:"promotion_id" jsonschema:"promotion ID"`
+	Budget      float64 `json:"budget" jsonschema:"new budget amount"`
+}
+
+type updatePromotionBudgetInput struct {
+	AdvertiserID int64             `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	Promotions   []promotionBudget `json:"promotions" jsonschema:"the promotions to update, each with its new budget"`
+	DryRun       bool              `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+type promotionBid struct {
+	PromotionID int64   `json:"promotion_id" jsonschema:"promotion ID"`
+	Bid         float64 `json:"bid" jsonschema:"new bid"`
+}
+
+type updatePromotionBidInput struct {
+	AdvertiserID int64          `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	Promotions   []promotionBid `json:"promotions" jsonschema:"the promotions to update, each with its new bid"`
+	DryRun       bool           `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+// ProjectChange is one project's state before and after a write.
+type ProjectChange struct {
+	ProjectID   int64  `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	Before      State  `json:"before"`
+	After       State  `json:"after"`
+}
+
+// PromotionChange is one promotion's state before and after a write.
+type PromotionChange struct {
+	PromotionID   int64  `json:"promotion_id"`
+	PromotionName string `json:"promotion_name"`
+	ProjectID     int64  `json:"project_id"`
+	Before        State  `json:"before"`
+	After         State  `json:"after"`
+}
+
+func projectChange(p oceanengine.Project) ProjectChange {
+	s := State{
+		Status:     firstNonEmpty(p.StatusFirst, p.Status),
+		OptStatus:  p.OptStatus,
+		Budget:     p.DeliverySetting.Budget,
+		BudgetMode: p.DeliverySetting.BudgetMode,
+	}
+	return ProjectChange{ProjectID: p.ID, ProjectName: p.Name, Before: s, After: s}
+}
+
+func promotionChange(p oceanengine.Promotion) PromotionChange {
+	s := State{
+		Status:     firstNonEmpty(p.StatusFirst, p.Status),
+		OptStatus:  p.OptStatus,
+		Budget:     p.Budget,
+		BudgetMode: p.BudgetMode,
+		Bid:        p.Bid,
+	}
+	if p.CpaBid > 0 {
+		s.Bid = p.CpaBid
+	}
+	return PromotionChange{PromotionID: p.ID, PromotionName: p.Name, ProjectID: p.ProjectID, Before: s, After: s}
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// v3OptStatus maps an opt_status argument to the v3.0 opt_status.
+var v3OptStatus = map[string]string{
+	"enable":  oceanengine.OptStatusEnable,
+	"disable": oceanengine.OptStatusDisable,
+}
+
+func registerV3WriteTools(srv *mcp.Server, w *writeTools, mode string) {
+	const perItem = " Ocean Engine may apply some items and reject others; result lists which."
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolProjectStatus,
+		Description: mode + ": enable or disable Ocean Engine (巨量引擎) projects (项目) on the upgraded 巨量广告 model, several per call. This mutates the live account unless dry_run is set. The user is asked to confirm the change first." + perItem,
+		Annotations: w.writeTool("Update project status", true, true),
+	}, audited(w, func(in updateProjectStatusInput) int64 { return in.AdvertiserID }, w.updateProjectStatus))
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolProjectBudget,
+		Description: mode + ": set new budgets for Ocean Engine (巨量引擎) projects (项目), several per call. This mutates the live account unless dry_run is set. The user is asked to confirm the change first." + perItem,
+		Annotations: w.writeTool("Update project budget", false, true),
+	}, audited(w, func(in updateProjectBudgetInput) int64 { return in.AdvertiserID }, w.updateProjectBudget))
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolPromotionStatus,
+		Description: mode + ": enable or disable Ocean Engine (巨量引擎) promotions (广告/单元) on the upgraded 巨量广告 model, several per call. This mutates the live account unless dry_run is set. The user is asked to confirm the change first." + perItem,
+		Annotations: w.writeTool("Update promotion status", true, true),
+	}, audited(w, func(in updatePromotionStatusInput) int64 { return in.AdvertiserID }, w.updatePromotionStatus))
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolPromotionBudget,
+		Description: mode + ": set new budgets for Ocean Engine (巨量引擎) promotions (广告/单元), several per call. This mutates the live account unless dry_run is set. The user is asked to confirm the change first." + perItem,
+		Annotations: w.writeTool("Update promotion budget", false, true),
+	}, audited(w, func(in updatePromotionBudgetInput) int64 { return in.AdvertiserID }, w.updatePromotionBudget))
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolPromotionBid,
+		Description: mode + ": set new bids for Ocean Engine (巨量引擎) promotions (广告/单元), several per call. This mutates the live account unless dry_run is set. The user is asked to confirm the change first." + perItem,
+		Annotations: w.writeTool("Update promotion bid", false, true),
+	}, audited(w, func(in updatePromotionBidInput) int64 { return in.AdvertiserID }, w.updatePromotionBid))
+}
+
+func (w *writeTools) updateProjectStatus(ctx context.Context, req *mcp.CallToolRequest, in updateProjectStatusInput, e *AuditEntry) (*writeOutput, error) {
+	opt, ok := v3OptStatus[in.OptStatus]
+	if !ok {
+		return nil, fmt.Errorf("opt_status must be one of enable, disable")
+	}
+	if err := w.checkBatch(req, "project", in.AdvertiserID, in.ProjectIDs); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkStatus("projects", in.OptStatus, len(in.ProjectIDs)); err != nil {
+		return nil, err
+	}
+	cur, err := currentProjects(ctx, w.client, in.AdvertiserID, in.ProjectIDs)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]ProjectChange, len(in.ProjectIDs))
+	labels := make([]string, len(in.ProjectIDs))
+	for i, id := range in.ProjectIDs {
+		changes[i] = projectChange(cur[id])
+		changes[i].After.OptStatus = opt
+		labels[i] = label(changes[i].ProjectName, id)
+	}
+	e.ProjectChanges = changes
+	summary := statusSummary(in.AdvertiserID, in.OptStatus, "project", labels)
+	return w.commit(ctx, req, e, &writeOutput{ProjectChanges: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdateProjectStatus(ctx, in.AdvertiserID, in.ProjectIDs, opt)
+	})
+}
+
+func (w *writeTools) updateProjectBudget(ctx context.Context, req *mcp.CallToolRequest, in updateProjectBudgetInput, e *AuditEntry) (*writeOutput, error) {
+	ids := make([]int64, len(in.Projects))
+	for i, p := range in.Projects {
+		if p.Budget <= 0 {
+			return nil, fmt.Errorf("budget for project %d must be positive", p.ProjectID)
+		}
+		ids[i] = p.ProjectID
+	}
+	if err := w.checkBatch(req, "project", in.AdvertiserID, ids); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkCount("projects", len(ids)); err != nil {
+		return nil, err
+	}
+	cur, err := currentProjects(ctx, w.client, in.AdvertiserID, ids)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]ProjectChange, len(in.Projects))
+	items := make([]string, len(in.Projects))
+	budgets := make([]oceanengine.ProjectBudget, len(in.Projects))
+	for i, p := range in.Projects {
+		c := projectChange(cur[p.ProjectID])
+		mode := finiteBudgetMode(c.Before.BudgetMode)
+		if err := w.policy.checkBudget("project "+strconv.FormatInt(p.ProjectID, 10), c.Before, p.Budget, mode); err != nil {
+			return nil, err
+		}
+		c.After.Budget, c.After.BudgetMode = p.Budget, mode
+		changes[i] = c
+		items[i] = fromTo(label(c.ProjectName, c.ProjectID), budgetString(c.Before), budgetString(c.After))
+		budgets[i] = oceanengine.ProjectBudget{ProjectID: p.ProjectID, Budget: p.Budget}
+	}
+	e.ProjectChanges = changes
+	summary := valueSummary(in.AdvertiserID, "budget", "project", items)
+	return w.commit(ctx, req, e, &writeOutput{ProjectChanges: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdateProjectBudget(ctx, in.AdvertiserID, budgets)
+	})
+}
+
+func (w *writeTools) updatePromotionStatus(ctx context.Context, req *mcp.CallToolRequest, in updatePromotionStatusInput, e *AuditEntry) (*writeOutput, error) {
+	opt, ok := v3OptStatus[in.OptStatus]
+	if !ok {
+		return nil, fmt.Errorf("opt_status must be one of enable, disable")
+	}
+	if err := w.checkBatch(req, "promotion", in.AdvertiserID, in.PromotionIDs); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkStatus("promotions", in.OptStatus, len(in.PromotionIDs)); err != nil {
+		return nil, err
+	}
+	cur, err := currentPromotions(ctx, w.client, in.AdvertiserID, in.PromotionIDs)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]PromotionChange, len(in.PromotionIDs))
+	labels := make([]string, len(in.PromotionIDs))
+	for i, id := range in.PromotionIDs {
+		changes[i] = promotionChange(cur[id])
+		changes[i].After.OptStatus = opt
+		labels[i] = label(changes[i].PromotionName, id)
+	}
+	e.PromotionChanges = changes
+	summary := statusSummary(in.AdvertiserID, in.OptStatus, "promotion", labels)
+	return w.commit(ctx, req, e, &writeOutput{PromotionChanges: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdatePromotionStatus(ctx, in.AdvertiserID, in.PromotionIDs, opt)
+	})
+}
+
+func (w *writeTools) updatePromotionBudget(ctx context.Context, req *mcp.CallToolRequest, in updatePromotionBudgetInput, e *AuditEntry) (*writeOutput, error) {
+	ids := make([]int64, len(in.Promotions))
+	for i, p := range in.Promotions {
+		if p.Budget <= 0 {
+			return nil, fmt.Errorf("budget for promotion %d must be positive", p.PromotionID)
+		}
+		ids[i] = p.PromotionID
+	}
+	if err := w.checkBatch(req, "promotion", in.AdvertiserID, ids); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkCount("promotions", len(ids)); err != nil {
+		return nil, err
+	}
+	cur, err := currentPromotions(ctx, w.client, in.AdvertiserID, ids)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]PromotionChange, len(in.Promotions))
+	items := make([]string, len(in.Promotions))
+	budgets := make([]oceanengine.PromotionBudget, len(in.Promotions))
+	for i, p := range in.Promotions {
+		c := promotionChange(cur[p.PromotionID])
+		mode := finiteBudgetMode(c.Before.BudgetMode)
+		if err := w.policy.checkBudget("promotion "+strconv.FormatInt(p.PromotionID, 10), c.Before, p.Budget, mode); err != nil {
+			return nil, err
+		}
+		c.After.Budget, c.After.BudgetMode = p.Budget, mode
+		changes[i] = c
+		items[i] = fromTo(label(c.PromotionName, c.PromotionID), budgetString(c.Before), budgetString(c.After))
+		budgets[i] = oceanengine.PromotionBudget{PromotionID: p.PromotionID, Budget: p.Budget}
+	}
+	e.PromotionChanges = changes
+	summary := valueSummary(in.AdvertiserID, "budget", "promotion", items)
+	return w.commit(ctx, req, e, &writeOutput{PromotionChanges: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdatePromotionBudget(ctx, in.AdvertiserID, budgets)
+	})
+}
+
+func (w *writeTools) updatePromotionBid(ctx context.Context, req *mcp.CallToolRequest, in updatePromotionBidInput, e *AuditEntry) (*writeOutput, error) {
+	ids := make([]int64, len(in.Promotions))
+	for i, p := range in.Promotions {
+		ids[i] = p.PromotionID
+	}
+	if err := w.checkBatch(req, "promotion", in.AdvertiserID, ids); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkCount("promotions", len(ids)); err != nil {
+		return nil, err
+	}
+	for _, p := range in.Promotions {
+		if p.Bid <= 0 {
+			return nil, fmt.Errorf("bid for promotion %d must be positive", p.PromotionID)
+		}
+		if err := w.policy.checkBid("promotion "+strconv.FormatInt(p.PromotionID, 10), p.Bid); err != nil {
+			return nil, err
+		}
+	}
+	cur, err := currentPromotions(ctx, w.client, in.AdvertiserID, ids)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]PromotionChange, len(in.Promotions))
+	items := make([]string, len(in.Promotions))
+	bids := make([]oceanengine.PromotionBid, len(in.Promotions))
+	for i, p := range in.Promotions {
+		c := promotionChange(cur[p.PromotionID])
+		c.After.Bid = p.Bid
+		changes[i] = c
+		items[i] = fromTo(label(c.PromotionName, c.PromotionID), bidString(c.Before), bidString(c.After))
+		bids[i] = oceanengine.PromotionBid{PromotionID: p.PromotionID, Bid: p.Bid}
+	}
+	e.PromotionChanges = changes
+	summary := valueSummary(in.AdvertiserID, "bid", "promotion", items)
+	return w.commit(ctx, req, e, &writeOutput{PromotionChanges: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdatePromotionBid(ctx, in.AdvertiserID, bids)
+	})
+}
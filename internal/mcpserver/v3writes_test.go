@@ -0,0 +1,121 @@
+package mcpserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// v3API serves the v3.0 project and promotion list endpoints and answers
+// writes with reply, recording their bodies by path.
+func v3API(t *testing.T, reply string, writes map[string]string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open_api/v3.0/project/list/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"project_id":11,"name":"P","status_first":"PROJECT_STATUS_ENABLE","opt_status":"ENABLE",
+				"delivery_setting":{"budget":1000,"budget_mode":"BUDGET_MODE_DAY"}}],"page_info":{"page":1,"total_page":1}}}`))
+		case "/open_api/v3.0/promotion/list/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[
+				{"promotion_id":5,"promotion_name":"U5","project_id":11,"opt_status":"ENABLE","cpa_bid":20},
+				{"promotion_id":6,"promotion_name":"U6","project_id":11,"opt_status":"ENABLE","cpa_bid":25}],"page_info":{"page":1,"total_page":1}}}`))
+		default:
+			if !strings.Contains(r.URL.Path, "/update/") {
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			b, _ := io.ReadAll(r.Body)
+			writes[r.URL.Path] = string(b)
+			_, _ = w.Write([]byte(reply))
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestPromotionBidPartialFailure(t *testing.T) {
+	writes := map[string]string{}
+	ts := v3API(t, `{"code":0,"request_id":"req-v3","data":{"promotion_ids":[5],"errors":[{"promotion_id":6,"error_message":"bid below floor"}]}}`, writes)
+	sink, err := NewFileAuditSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	cs := connect(t, ts.URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow, Audit: sink})
+
+	res, out := callWriteTool(t, cs, toolPromotionBid, map[string]any{"advertiser_id": 1,
+		"promotions": []map[string]any{{"promotion_id": 5, "bid": 22}, {"promotion_id": 6, "bid": 1}}})
+	if res.IsError {
+		t.Fatalf("partial failure reported as a tool error: %+v", res.Content)
+	}
+	if out.OK || out.Result == nil || len(out.Result.Succeeded) != 1 || len(out.Result.Failed) != 1 || out.Result.Failed[0].Message != "bid below floor" {
+		t.Fatalf("out = %+v", out)
+	}
+	if c := out.PromotionChanges[0]; c.Before.Bid != 20 || c.After.Bid != 22 {
+		t.Errorf("change = %+v", c)
+	}
+	if got := writes["/open_api/v3.0/promotion/bid/update/"]; got != `{"advertiser_id":1,"data":[{"promotion_id":5,"bid":22},{"promotion_id":6,"bid":1}]}` {
+		t.Errorf("body = %s", got)
+	}
+
+	entries, err := sink.Entries(context.Background(), AuditQuery{ID: out.AuditID})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("audit entries = %v, %v", entries, err)
+	}
+	if e := entries[0]; e.Outcome != OutcomePartial || e.Result == nil || len(e.PromotionChanges) != 2 {
+		t.Fatalf("audit entry = %+v", e)
+	}
+}
+
+func TestProjectWrites(t *testing.T) {
+	writes := map[string]string{}
+	ts := v3API(t, `{"code":0,"data":{"project_ids":[11]}}`, writes)
+	cs := connect(t, ts.URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow, Policy: WritePolicy{MaxBudget: 1500}})
+
+	res, out := callWriteTool(t, cs, toolProjectStatus, map[string]any{"advertiser_id": 1, "project_ids": []int64{11}, "opt_status": "disable"})
+	if res.IsError || !out.OK || out.ProjectChanges[0].After.OptStatus != "DISABLE" {
+		t.Fatalf("status: %+v %+v", res.Content, out)
+	}
+	if got := writes["/open_api/v3.0/project/status/update/"]; got != `{"advertiser_id":1,"data":[{"opt_status":"DISABLE","project_id":11}]}` {
+		t.Errorf("status body = %s", got)
+	}
+
+	res, out = callWriteTool(t, cs, toolProjectBudget, map[string]any{"advertiser_id": 1, "projects": []map[string]any{{"project_id": 11, "budget": 1200}}, "dry_run": true})
+	if res.IsError || !out.DryRun || out.ProjectChanges[0].Before.Budget != 1000 || out.ProjectChanges[0].After.Budget != 1200 {
+		t.Fatalf("budget dry run: %+v %+v", res.Content, out)
+	}
+	if _, ok := writes["/open_api/v3.0/project/budget/update/"]; ok {
+		t.Error("dry run reached Ocean Engine")
+	}
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+		want string
+	}{
+		{toolProjectStatus, map[string]any{"advertiser_id": 1, "project_ids": []int64{11}, "opt_status": "delete"}, "enable, disable"},
+		{toolProjectBudget, map[string]any{"advertiser_id": 1, "projects": []map[string]any{{"project_id": 11, "budget": 2000}}}, "maximum budget of 1500"},
+		{toolPromotionStatus, map[string]any{"advertiser_id": 1, "promotion_ids": []int64{9}, "opt_status": "enable"}, "promotion 9 not found"},
+	} {
+		res, _ := callWriteTool(t, cs, tc.tool, tc.args)
+		if msg := errorText(res); !strings.Contains(msg, tc.want) {
+			t.Errorf("%s: error %q, want it to mention %q", tc.tool, msg, tc.want)
+		}
+	}
+}
+
+func TestPromotionBidAuthorizedBeforePolicy(t *testing.T) {
+	writes := map[string]string{}
+	ts := v3API(t, `{"code":0,"data":{}}`, writes)
+	cs := connect(t, ts.URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow,
+		AllowedAdvertiserIDs: []int64{2}, Policy: WritePolicy{MaxBid: 10}})
+
+	// A caller outside the allowlist must not learn the server's policy.
+	res, _ := callWriteTool(t, cs, toolPromotionBid, map[string]any{"advertiser_id": 1, "promotions": []map[string]any{{"promotion_id": 5, "bid": 11}}})
+	if msg := errorText(res); !strings.Contains(msg, "access denied") {
+		t.Fatalf("error %q, want access denied", msg)
+	}
+}
@@ -0,0 +1,250 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/virgoC0der/go-mcp/internal/oceanengine"
+)
+
+// ---------------------------------------------------------------------------
+// Write tools (only registered when EnableWrites or DryRun is true)
+// ---------------------------------------------------------------------------
+
+type updateStatusInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	CampaignIDs  []int64 `json:"campaign_ids" jsonschema:"campaign IDs to update"`
+	OptStatus    string  `json:"opt_status" jsonschema:"one of: enable, disable, delete"`
+	DryRun       bool    `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+type updateBudgetInput struct {
+	AdvertiserID int64   `json:"advertiser_id" jsonschema:"Ocean Engine advertiser (account) ID"`
+	CampaignID   int64   `json:"campaign_id" jsonschema:"campaign ID to update"`
+	Budget       float64 `json:"budget" jsonschema:"new budget amount"`
+	BudgetMode   string  `json:"budget_mode,omitempty" jsonschema:"budget mode; defaults to BUDGET_MODE_DAY"`
+	DryRun       bool    `json:"dry_run,omitempty" jsonschema:"validate and preview the change without applying it"`
+}
+
+// State is the part of a campaign or ad the write tools change.
+type State struct {
+	Status     string  `json:"status"`
+	OptStatus  string  `json:"opt_status,omitempty"`
+	Budget     float64 `json:"budget"`
+	BudgetMode string  `json:"budget_mode"`
+	Bid        float64 `json:"bid,omitempty"` // ads only
+}
+
+func stateOf(c oceanengine.Campaign) State {
+	return State{Status: c.Status, OptStatus: c.OptStatus, Budget: c.Budget, BudgetMode: c.BudgetMode}
+}
+
+// CampaignChange is one campaign's state before and after a write.
+type CampaignChange struct {
+	CampaignID   int64  `json:"campaign_id"`
+	CampaignName string `json:"campaign_name"`
+	Before       State  `json:"before"`
+	After        State  `json:"after"`
+}
+
+type writeOutput struct {
+	// OK is true if every targeted item was changed.
+	OK               bool              `json:"ok"`
+	DryRun           bool              `json:"dry_run,omitempty" jsonschema:"true if nothing was changed; changes shows what would have been"`
+	Changes          []CampaignChange  `json:"changes,omitempty"`
+	AdChanges        []AdChange        `json:"ad_changes,omitempty"`
+	ProjectChanges   []ProjectChange   `json:"project_changes,omitempty"`
+	PromotionChanges []PromotionChange `json:"promotion_changes,omitempty"`
+	// Result is set by batch writes that report per-item outcomes.
+	Result  *oceanengine.BatchResult `json:"result,omitempty" jsonschema:"which items Ocean Engine applied and why the others failed"`
+	AuditID string                   `json:"audit_id,omitempty" jsonschema:"ID of the audit log entry recording this call"`
+}
+
+// optStatusResult maps an opt_status argument to the campaign status it leads
+// to.
+var optStatusResult = map[string]string{
+	"enable":  "CAMPAIGN_STATUS_ENABLE",
+	"disable": "CAMPAIGN_STATUS_DISABLE",
+	"delete":  "CAMPAIGN_STATUS_DELETE",
+}
+
+// Write tool names, also recorded in audit entries.
+const (
+	toolUpdateStatus = "oceanengine_update_campaign_status"
+	toolUpdateBudget = "oceanengine_update_campaign_budget"
+	toolUndoChange   = "oceanengine_undo_change"
+	toolAdStatus     = "oceanengine_update_ad_status"
+	toolAdBudget     = "oceanengine_update_ad_budget"
+	toolAdBid        = "oceanengine_update_ad_bid"
+
+	toolProjectStatus   = "oceanengine_update_project_status"
+	toolProjectBudget   = "oceanengine_update_project_budget"
+	toolPromotionStatus = "oceanengine_update_promotion_status"
+	toolPromotionBudget = "oceanengine_update_promotion_budget"
+	toolPromotionBid    = "oceanengine_update_promotion_bid"
+)
+
+// writeTools holds what the write tool handlers share.
+type writeTools struct {
+	client *oceanengine.Client
+	guard  *guard
+	policy WritePolicy
+	// dryRun makes every write a dry run, whatever the call asks for.
+	dryRun          bool
+	confirmFallback ConfirmFallback
+	audit           AuditSink
+}
+
+func registerWriteTools(srv *mcp.Server, client *oceanengine.Client, g *guard, cfg Config) {
+	w := &writeTools{client: client, guard: g, policy: cfg.Policy, dryRun: cfg.DryRun || !cfg.EnableWrites,
+		confirmFallback: cfg.ConfirmFallback, audit: cfg.Audit}
+	mode := "WRITE"
+	if w.dryRun {
+		mode = "DRY RUN ONLY (writes are disabled on this server, calls only preview the change)"
+	}
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolUpdateStatus,
+		Description: mode + ": enable, disable or delete Ocean Engine (巨量引擎) campaigns. This mutates the live account unless dry_run is set. The user is asked to confirm the change first. Returns each campaign's status before and after.",
+		Annotations: w.writeTool("Update campaign status", true, true),
+	}, audited(w, func(in updateStatusInput) int64 { return in.AdvertiserID }, w.updateStatus))
+
+	mcp.AddTool(srv, &mcp.Tool{
+		Name:        toolUpdateBudget,
+		Description: mode + ": set a new budget for an Ocean Engine (巨量引擎) campaign. This mutates the live account unless dry_run is set. The user is asked to confirm the change first. Returns the budget before and after.",
+		Annotations: w.writeTool("Update campaign budget", false, true),
+	}, audited(w, func(in updateBudgetInput) int64 { return in.AdvertiserID }, w.updateBudget))
+
+	registerAdWriteTools(srv, w, mode)
+	registerV3WriteTools(srv, w, mode)
+	if w.audit != nil {
+		registerUndoTool(srv, w, mode)
+	}
+}
+
+// checkBatch runs the checks every batch write of campaigns, ads, projects or
+// promotions (kind) shares. It refuses duplicate IDs, which would make the
+// change ambiguous.
+func (w *writeTools) checkBatch(req *mcp.CallToolRequest, kind string, advertiserID int64, ids []int64) error {
+	if advertiserID == 0 || len(ids) == 0 {
+		return fmt.Errorf("advertiser_id and at least one %s are required", kind)
+	}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return fmt.Errorf("%s %d is listed more than once", kind, id)
+		}
+		seen[id] = true
+	}
+	return w.guard.authorize(req, advertiserID)
+}
+
+// commit applies a planned write whose changes are already in out and e: a
+// dry run returns out as is, a real one asks the user to confirm summary,
+// calls apply and records its per-item result, if it has one.
+func (w *writeTools) commit(ctx context.Context, req *mcp.CallToolRequest, e *AuditEntry, out *writeOutput, dryRun bool, summary string,
+	apply func(context.Context) (*oceanengine.BatchResult, error),
+) (*writeOutput, error) {
+	if w.dryRun || dryRun {
+		out.DryRun = true
+		return out, nil
+	}
+	if err := w.confirm(ctx, req, summary); err != nil {
+		return nil, err
+	}
+	ctx, requestIDs := oceanengine.WithRequestIDs(ctx)
+	res, err := apply(ctx)
+	e.RequestIDs = requestIDs()
+	if err != nil {
+		e.Outcome = OutcomeFailed
+		return nil, toolError(err)
+	}
+	out.Result, e.Result = res, res
+	switch {
+	case res == nil || len(res.Failed) == 0:
+		out.OK = true
+	case len(res.Succeeded) == 0:
+		e.Outcome = OutcomeFailed
+	default:
+		e.Outcome = OutcomePartial
+	}
+	return out, nil
+}
+
+// planStatus validates a status change and returns the changes it would make.
+func (w *writeTools) planStatus(ctx context.Context, req *mcp.CallToolRequest, in updateStatusInput) ([]CampaignChange, error) {
+	after, ok := optStatusResult[in.OptStatus]
+	if !ok {
+		return nil, fmt.Errorf("opt_status must be one of enable, disable, delete")
+	}
+	if err := w.checkBatch(req, "campaign", in.AdvertiserID, in.CampaignIDs); err != nil {
+		return nil, err
+	}
+	if err := w.policy.checkStatus("campaigns", in.OptStatus, len(in.CampaignIDs)); err != nil {
+		return nil, err
+	}
+	cur, err := currentCampaigns(ctx, w.client, in.AdvertiserID, in.CampaignIDs)
+	if err != nil {
+		return nil, toolError(err)
+	}
+	changes := make([]CampaignChange, 0, len(in.CampaignIDs))
+	for _, id := range in.CampaignIDs {
+		c := cur[id]
+		ch := CampaignChange{CampaignID: id, CampaignName: c.Name, Before: stateOf(c), After: stateOf(c)}
+		ch.After.Status, ch.After.OptStatus = after, after
+		changes = append(changes, ch)
+	}
+	return changes, nil
+}
+
+func (w *writeTools) updateStatus(ctx context.Context, req *mcp.CallToolRequest, in updateStatusInput, e *AuditEntry) (*writeOutput, error) {
+	changes, err := w.planStatus(ctx, req, in)
+	if err != nil {
+		return nil, err
+	}
+	e.Changes = changes
+	summary := statusSummary(in.AdvertiserID, in.OptStatus, "campaign", campaignLabels(changes))
+	return w.commit(ctx, req, e, &writeOutput{Changes: changes}, in.DryRun, summary, func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdateCampaignStatus(ctx, in.AdvertiserID, in.CampaignIDs, in.OptStatus)
+	})
+}
+
+// planBudget validates a budget change and returns the change it would make.
+func (w *writeTools) planBudget(ctx context.Context, req *mcp.CallToolRequest, in updateBudgetInput) ([]CampaignChange, error) {
+	if in.AdvertiserID == 0 || in.CampaignID == 0 {
+		return nil, fmt.Errorf("advertiser_id and campaign_id are required")
+	}
+	if err := w.guard.authorize(req, in.AdvertiserID); err != nil {
+		return nil, err
+	}
+	if in.Budget <= 0 && in.BudgetMode != budgetModeInfinite {
+		return nil, fmt.Errorf("budget must be positive")
+	}
+	cur, err := currentCampaigns(ctx, w.client, in.AdvertiserID, []int64{in.CampaignID})
+	if err != nil {
+		return nil, toolError(err)
+	}
+	c := cur[in.CampaignID]
+	if err := w.policy.checkBudget(fmt.Sprintf("campaign %d", c.ID), stateOf(c), in.Budget, in.BudgetMode); err != nil {
+		return nil, err
+	}
+	ch := CampaignChange{CampaignID: c.ID, CampaignName: c.Name, Before: stateOf(c), After: stateOf(c)}
+	ch.After.Budget, ch.After.BudgetMode = in.Budget, in.BudgetMode
+	return []CampaignChange{ch}, nil
+}
+
+func (w *writeTools) updateBudget(ctx context.Context, req *mcp.CallToolRequest, in updateBudgetInput, e *AuditEntry) (*writeOutput, error) {
+	if in.BudgetMode == "" {
+		in.BudgetMode = "BUDGET_MODE_DAY"
+	}
+	changes, err := w.planBudget(ctx, req, in)
+	if err != nil {
+		return nil, err
+	}
+	e.Changes = changes
+	return w.commit(ctx, req, e, &writeOutput{Changes: changes}, in.DryRun, budgetSummary(in.AdvertiserID, changes[0]), func(ctx context.Context) (*oceanengine.BatchResult, error) {
+		return w.client.UpdateCampaignBudget(ctx, in.AdvertiserID, in.CampaignID, in.Budget, in.BudgetMode)
+	})
+}
@@ -0,0 +1,144 @@
+package mcpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// campaignAPI serves /2/campaign/get/ with the given campaigns JSON and counts
+// calls to the campaign update endpoints.
+func campaignAPI(t *testing.T, campaigns string, updates *int) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/open_api/2/campaign/get/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":` + campaigns + `,"page_info":{"page":1,"total_page":1}}}`))
+		case strings.HasPrefix(r.URL.Path, "/open_api/2/campaign/update/"):
+			*updates++
+			_, _ = w.Write([]byte(`{"code":0,"request_id":"req-w","data":{}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestDryRunReturnsDiff(t *testing.T) {
+	var updates int
+	ts := campaignAPI(t, `[
+		{"id":7,"name":"A","status":"CAMPAIGN_STATUS_ENABLE","budget":400,"budget_mode":"BUDGET_MODE_DAY"},
+		{"id":8,"name":"B","status":"CAMPAIGN_STATUS_ENABLE","budget":300,"budget_mode":"BUDGET_MODE_DAY"}]`, &updates)
+
+	cs := connect(t, ts.URL, Config{EnableWrites: true})
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_update_campaign_status",
+		Arguments: map[string]any{"advertiser_id": 1, "campaign_ids": []int64{7, 8}, "opt_status": "disable", "dry_run": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("dry run failed: %+v", res.Content)
+	}
+	var out writeOutput
+	decodeStructured(t, res, &out)
+	if !out.DryRun || out.OK || len(out.Changes) != 2 {
+		t.Fatalf("out = %+v, want a dry run with two changes", out)
+	}
+	if c := out.Changes[1]; c.CampaignName != "B" || c.Before.Status != "CAMPAIGN_STATUS_ENABLE" || c.After.Status != "CAMPAIGN_STATUS_DISABLE" {
+		t.Fatalf("change = %+v", c)
+	}
+	if updates != 0 {
+		t.Fatal("dry run reached an update endpoint")
+	}
+}
+
+func TestDryRunOnlyServer(t *testing.T) {
+	var updates int
+	ts := campaignAPI(t, `[{"id":7,"name":"A","budget":400,"budget_mode":"BUDGET_MODE_DAY"}]`, &updates)
+
+	// With writes disabled, DryRun still registers the tools, and every call
+	// is a dry run even if it does not ask for one.
+	cs := connect(t, ts.URL, Config{DryRun: true})
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_update_campaign_budget",
+		Arguments: map[string]any{"advertiser_id": 1, "campaign_id": 7, "budget": 500},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out writeOutput
+	decodeStructured(t, res, &out)
+	if !out.DryRun || out.Changes[0].Before.Budget != 400 || out.Changes[0].After.Budget != 500 {
+		t.Fatalf("out = %+v", out)
+	}
+	if updates != 0 {
+		t.Fatal("dry-run-only server applied a write")
+	}
+}
+
+func TestWriteUnknownCampaign(t *testing.T) {
+	var updates int
+	ts := campaignAPI(t, `[]`, &updates)
+
+	cs := connect(t, ts.URL, Config{EnableWrites: true})
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "oceanengine_update_campaign_status",
+		Arguments: map[string]any{"advertiser_id": 1, "campaign_ids": []int64{9}, "opt_status": "enable"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "campaign 9 not found") {
+		t.Fatalf("want not-found error, got %+v", res.Content)
+	}
+	if updates != 0 {
+		t.Fatal("write to an unknown campaign reached Ocean Engine")
+	}
+}
+
+func TestCampaignStatusPartialFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open_api/2/campaign/get/":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"id":7,"name":"A","status":"CAMPAIGN_STATUS_ENABLE"},{"id":8,"name":"B","status":"CAMPAIGN_STATUS_ENABLE"}],"page_info":{"page":1,"total_page":1}}}`))
+		case "/open_api/2/campaign/update/status/":
+			_, _ = w.Write([]byte(`{"code":0,"request_id":"req-w","data":{"campaign_ids":[7],"errors":[{"campaign_id":8,"error_message":"campaign is locked"}]}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	cs := connect(t, ts.URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow})
+	res, out := callWriteTool(t, cs, toolUpdateStatus, map[string]any{"advertiser_id": 1, "campaign_ids": []int64{7, 8}, "opt_status": "disable"})
+	if res.IsError {
+		t.Fatalf("partial failure reported as a tool error: %+v", res.Content)
+	}
+	if out.OK || out.Result == nil || len(out.Result.Succeeded) != 1 || out.Result.Succeeded[0] != 7 {
+		t.Fatalf("out = %+v", out)
+	}
+	if f := out.Result.Failed; len(f) != 1 || f[0].ID != 8 || f[0].Message != "campaign is locked" {
+		t.Fatalf("failed = %+v", f)
+	}
+}
+
+func TestCampaignStatusRejectsDuplicates(t *testing.T) {
+	var updates int
+	ts := campaignAPI(t, `[{"id":7,"name":"A","status":"CAMPAIGN_STATUS_ENABLE"}]`, &updates)
+
+	cs := connect(t, ts.URL, Config{EnableWrites: true, ConfirmFallback: ConfirmAllow})
+	res, _ := callWriteTool(t, cs, toolUpdateStatus, map[string]any{"advertiser_id": 1, "campaign_ids": []int64{7, 7}, "opt_status": "disable"})
+	if msg := errorText(res); !strings.Contains(msg, "campaign 7 is listed more than once") {
+		t.Fatalf("error %q, want a duplicate error", msg)
+	}
+	if updates != 0 {
+		t.Fatal("duplicate campaign IDs reached Ocean Engine")
+	}
+}
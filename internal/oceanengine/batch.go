@@ -0,0 +1,124 @@
+package oceanengine
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+)
+
+// BatchResult reports which items of a batch write Ocean Engine applied.
+// Batch endpoints succeed as a whole (code 0) even when some items fail, so
+// callers must check Failed.
+type BatchResult struct {
+	Succeeded []int64     `json:"succeeded"`
+	Failed    []ItemError `json:"failed,omitempty"`
+}
+
+// ItemError is the reason one item of a batch write failed. ID is 0 if Ocean
+// Engine did not say which item it was; Succeeded is then left empty, since
+// which items were applied is unknown.
+type ItemError struct {
+	ID      int64  `json:"id"`
+	Message string `json:"message"`
+}
+
+// batchData decodes the data payload of a batch write. Endpoints report the
+// applied items as "<idKey>s" ("campaign_ids", "promotion_ids") or "success",
+// and failures as "errors" or "error_list" entries of an idKey and an
+// "error_message" or "message".
+type batchData struct {
+	idKey        string // the object's ID field, e.g. "campaign_id"
+	succeeded    []int64
+	hasSucceeded bool
+	failed       []ItemError
+	unidentified bool // some failure did not name its item
+}
+
+func (b *batchData) UnmarshalJSON(raw []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Some endpoints return an empty or non-object data payload on success.
+		return nil
+	}
+	for key, v := range fields {
+		switch key {
+		case "errors", "error_list":
+			var items []map[string]json.RawMessage
+			if err := json.Unmarshal(v, &items); err != nil {
+				continue
+			}
+			for _, item := range items {
+				e := b.itemError(item)
+				b.unidentified = b.unidentified || e.ID == 0
+				b.failed = append(b.failed, e)
+			}
+		case "success", b.idKey + "s":
+			var ids []int64
+			if err := json.Unmarshal(v, &ids); err != nil {
+				continue
+			}
+			b.succeeded = append(b.succeeded, ids...)
+			b.hasSucceeded = true
+		}
+	}
+	return nil
+}
+
+// itemError decodes one failure. Its ID is 0 if the entry does not carry the
+// object's ID. IDs are parsed from the raw number: v3.0 IDs have 19 digits,
+// more than a float64 holds exactly.
+func (b *batchData) itemError(item map[string]json.RawMessage) ItemError {
+	var e ItemError
+	var id json.Number
+	if json.Unmarshal(item[b.idKey], &id) == nil {
+		e.ID, _ = strconv.ParseInt(id.String(), 10, 64)
+	}
+	for _, key := range []string{"error_message", "message"} {
+		if json.Unmarshal(item[key], &e.Message) == nil && e.Message != "" {
+			break
+		}
+	}
+	return e
+}
+
+// result builds the BatchResult of a write of the requested IDs. If the
+// response did not list the succeeded IDs, every requested ID that did not
+// fail is taken to have succeeded — unless a failure did not say which item
+// it was, in which case none is.
+func (b *batchData) result(requested []int64) *BatchResult {
+	res := &BatchResult{Succeeded: b.succeeded, Failed: b.failed}
+	if !b.hasSucceeded && !b.unidentified {
+		res.Succeeded = slices.DeleteFunc(slices.Clone(requested), func(id int64) bool {
+			return slices.ContainsFunc(b.failed, func(e ItemError) bool { return e.ID == id })
+		})
+	}
+	if res.Succeeded == nil {
+		res.Succeeded = []int64{}
+	}
+	// An endpoint may name an item in both "success" and "<idKey>s".
+	slices.Sort(res.Succeeded)
+	res.Succeeded = slices.Compact(res.Succeeded)
+	return res
+}
+
+// postBatch posts a batch write of data for the requested IDs, in the usual
+// {advertiser_id, data} body, and decodes its per-item result. idKey is the
+// ID field of the written objects, e.g. "promotion_id".
+func (c *Client) postBatch(ctx context.Context, path string, advertiserID int64, data any, idKey string, requested []int64) (*BatchResult, error) {
+	body := map[string]any{
+		"advertiser_id": advertiserID,
+		"data":          data,
+	}
+	return c.postBatchBody(ctx, path, body, idKey, requested)
+}
+
+// postBatchBody posts a batch write for the requested IDs and decodes its
+// per-item result; see postBatch.
+func (c *Client) postBatchBody(ctx context.Context, path string, body any, idKey string, requested []int64) (*BatchResult, error) {
+	out := batchData{idKey: idKey}
+	if err := c.post(ctx, path, body, &out); err != nil {
+		return nil, err
+	}
+	return out.result(requested), nil
+}
@@ -0,0 +1,123 @@
+package oceanengine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBatchDataShapes(t *testing.T) {
+	for _, tc := range []struct {
+		idKey, data string
+		want        BatchResult
+	}{
+		{"promotion_id", `{"promotion_ids":[1,3],"errors":[{"promotion_id":2,"error_message":"bid too low"}]}`,
+			BatchResult{Succeeded: []int64{1, 3}, Failed: []ItemError{{ID: 2, Message: "bid too low"}}}},
+		{"campaign_id", `{"success":[3,1],"errors":[]}`, BatchResult{Succeeded: []int64{1, 3}}},
+		// No success list: everything that did not fail succeeded.
+		{"campaign_id", `{"errors":[{"campaign_id":2,"message":"no permission"}]}`,
+			BatchResult{Succeeded: []int64{1, 3}, Failed: []ItemError{{ID: 2, Message: "no permission"}}}},
+		// Other ID fields neither name the failed item nor count as applied.
+		{"promotion_id", `{"project_ids":[9],"errors":[{"advertiser_id":1,"project_id":9,"promotion_id":2,"error_message":"bid too low"}]}`,
+			BatchResult{Succeeded: []int64{1, 3}, Failed: []ItemError{{ID: 2, Message: "bid too low"}}}},
+		// A failure without the item's ID leaves the applied items unknown.
+		{"campaign_id", `{"errors":[{"advertiser_id":1,"error_message":"no permission"}]}`,
+			BatchResult{Succeeded: []int64{}, Failed: []ItemError{{Message: "no permission"}}}},
+		// Both success lists may name the same item.
+		{"campaign_id", `{"success":[1,3],"campaign_ids":[3]}`, BatchResult{Succeeded: []int64{1, 3}}},
+		{"campaign_id", `{}`, BatchResult{Succeeded: []int64{1, 2, 3}}},
+		{"campaign_id", `null`, BatchResult{Succeeded: []int64{1, 2, 3}}},
+	} {
+		b := batchData{idKey: tc.idKey}
+		if err := json.Unmarshal([]byte(tc.data), &b); err != nil {
+			t.Fatalf("%s: %v", tc.data, err)
+		}
+		if got := b.result([]int64{1, 2, 3}); !reflect.DeepEqual(*got, tc.want) {
+			t.Errorf("%s: result = %+v, want %+v", tc.data, *got, tc.want)
+		}
+	}
+}
+
+func TestUpdatePromotionBidPartialFailure(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open_api/v3.0/promotion/bid/update/" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		_, _ = w.Write([]byte(`{"code":0,"data":{"promotion_ids":[5],"errors":[{"promotion_id":6,"error_message":"promotion is deleted"}]}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	res, err := c.UpdatePromotionBid(context.Background(), 1, []PromotionBid{{PromotionID: 5, Bid: 20}, {PromotionID: 6, Bid: 21}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != `{"advertiser_id":1,"data":[{"promotion_id":5,"bid":20},{"promotion_id":6,"bid":21}]}` {
+		t.Errorf("body = %s", body)
+	}
+	if len(res.Succeeded) != 1 || res.Succeeded[0] != 5 || len(res.Failed) != 1 || res.Failed[0].Message != "promotion is deleted" {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestUpdateProjectStatusBody(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open_api/v3.0/project/status/update/" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		_, _ = w.Write([]byte(`{"code":0,"data":{"project_ids":[11,12]}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	res, err := c.UpdateProjectStatus(context.Background(), 1, []int64{11, 12}, OptStatusDisable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != `{"advertiser_id":1,"data":[{"opt_status":"DISABLE","project_id":11},{"opt_status":"DISABLE","project_id":12}]}` {
+		t.Errorf("body = %s", body)
+	}
+	if len(res.Succeeded) != 2 || len(res.Failed) != 0 {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestUpdateCampaignStatusPartialFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"code":0,"data":{"campaign_ids":[7],"errors":[{"campaign_id":8,"error_message":"campaign is deleted"}]}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	res, err := c.UpdateCampaignStatus(context.Background(), 1, []int64{7, 8}, "disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := BatchResult{Succeeded: []int64{7}, Failed: []ItemError{{ID: 8, Message: "campaign is deleted"}}}
+	if !reflect.DeepEqual(*res, want) {
+		t.Fatalf("result = %+v, want %+v", *res, want)
+	}
+}
+
+func TestBatchDataKeepsLargeIDs(t *testing.T) {
+	// 19-digit v3.0 IDs do not survive a round trip through float64.
+	const ok, bad = 7212345678901234561, 7212345678901234567
+	b := batchData{idKey: "promotion_id"}
+	if err := json.Unmarshal([]byte(`{"errors":[{"promotion_id":7212345678901234567,"error_message":"bid too low"}]}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	want := BatchResult{Succeeded: []int64{ok}, Failed: []ItemError{{ID: bad, Message: "bid too low"}}}
+	if got := b.result([]int64{ok, bad}); !reflect.DeepEqual(*got, want) {
+		t.Fatalf("result = %+v, want %+v", *got, want)
+	}
+}
@@ -14,6 +14,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -21,12 +24,19 @@ import (
 const DefaultBaseURL = "https://api.oceanengine.com"
 
 // Client talks to the Ocean Engine Marketing API. It obtains the access token
-// for each request from a TokenProvider, so callers can supply either a static
-// token or a self-refreshing OAuth token source.
+// for each request from a TokenProvider, so callers can supply a static token,
+// a self-refreshing OAuth token source, or a TokenRegistry that picks the token
+// authorized for each request's advertiser.
 type Client struct {
 	baseURL    string
 	tokens     TokenProvider
 	httpClient *http.Client
+	retry      RetryPolicy
+	limiter    *RateLimiter // nil when unlimited
+
+	// Developer app credentials, needed only by OAuth-scoped endpoints.
+	appID     uint64
+	appSecret string
 }
 
 // Option customizes a Client.
@@ -61,14 +71,25 @@ func WithTokenProvider(tp TokenProvider) Option {
 	}
 }
 
+// WithAppCredentials supplies the developer app ID and secret, which a few
+// OAuth-scoped endpoints (such as ListAuthorizedAdvertisers) require.
+func WithAppCredentials(appID uint64, secret string) Option {
+	return func(c *Client) {
+		c.appID = appID
+		c.appSecret = secret
+	}
+}
+
 // NewClient builds a Client. The accessToken is the OAuth access_token issued
 // by the Ocean Engine open platform; pass WithTokenProvider to manage refresh
-// automatically instead.
+// automatically instead. Reads are retried under DefaultRetryPolicy unless
+// WithRetryPolicy says otherwise.
 func NewClient(accessToken string, opts ...Option) *Client {
 	c := &Client{
 		baseURL:    DefaultBaseURL,
 		tokens:     StaticToken(accessToken),
 		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      DefaultRetryPolicy(),
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -76,6 +97,13 @@ func NewClient(accessToken string, opts ...Option) *Client {
 	return c
 }
 
+// Registry returns the client's TokenRegistry, or nil if it does not use one.
+// Callers that discover further advertisers add them to it.
+func (c *Client) Registry() *TokenRegistry {
+	reg, _ := c.tokens.(*TokenRegistry)
+	return reg
+}
+
 // envelope is the standard Ocean Engine response wrapper. Every endpoint
 // returns a non-zero Code on failure; Data carries the endpoint-specific
 // payload on success.
@@ -97,6 +125,17 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("oceanengine api error: code=%d message=%q request_id=%s", e.Code, e.Message, e.RequestID)
 }
 
+// HTTPError represents a non-2xx HTTP response that does not carry an Ocean
+// Engine envelope, such as an error page from a gateway or load balancer.
+type HTTPError struct {
+	StatusCode int
+	Body       string // leading bytes of the response body, for diagnostics
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("oceanengine: http %d: %s", e.StatusCode, e.Body)
+}
+
 // get performs an authenticated GET request and unmarshals the data field of
 // the response envelope into out.
 func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
@@ -126,21 +165,156 @@ func (c *Client) post(ctx context.Context, path string, body, out any) error {
 	return c.authedDo(req, out)
 }
 
-// authedDo attaches the access token from the provider and executes the request.
+// authedDo attaches the access token from the provider and executes the
+// request, retrying transient failures according to the client's RetryPolicy.
+// If the API rejects the token itself and the provider can invalidate it, the
+// request is replayed once with a fresh token; the rejection means nothing was
+// applied, so this is safe for writes too.
 func (c *Client) authedDo(req *http.Request, out any) error {
-	token, err := c.tokens.Token(req.Context())
+	return c.send(req, c.tokens, headerToken, out)
+}
+
+// send is authedDo with a given token provider and a given way of attaching
+// the token to the request.
+func (c *Client) send(req *http.Request, tokens TokenProvider, attach func(*http.Request, string), out any) error {
+	advertiserID := advertiserIDOf(req)
+	reauthed := false
+	attempt := 1
+	for sends := 0; ; sends++ {
+		r := req
+		if sends > 0 {
+			var err error
+			if r, err = rewind(req); err != nil {
+				return err
+			}
+		}
+		if c.limiter != nil {
+			if err := c.limiter.Wait(req.Context(), req.URL.Path, advertiserID); err != nil {
+				return err
+			}
+		}
+		token, err := c.attempt(r, tokens, attach, advertiserID, out)
+		if err == nil {
+			return nil
+		}
+		if !reauthed && reauth(tokens, token, err) {
+			reauthed = true
+			continue
+		}
+		if !c.retry.retryable(req, err, attempt) {
+			return err
+		}
+		if sleepCtx(req.Context(), c.retry.backoff(attempt)) != nil {
+			return err
+		}
+		attempt++
+	}
+}
+
+// attempt sends req once with a current access token for advertiserID,
+// returning the token it used.
+func (c *Client) attempt(req *http.Request, tokens TokenProvider, attach func(*http.Request, string), advertiserID int64, out any) (string, error) {
+	var token string
+	var err error
+	if ap, ok := tokens.(AdvertiserTokenProvider); ok {
+		token, err = ap.TokenFor(req.Context(), advertiserID)
+	} else {
+		token, err = tokens.Token(req.Context())
+	}
 	if err != nil {
-		return err
+		return "", err
 	}
-	// Ocean Engine authenticates via the Access-Token header.
+	attach(req, token)
+	return token, doRequest(c.httpClient, req, out)
+}
+
+// headerToken attaches token the way almost every endpoint expects it, in the
+// Access-Token header.
+func headerToken(req *http.Request, token string) {
 	req.Header.Set("Access-Token", token)
-	return doRequest(c.httpClient, req, out)
+}
+
+// queryToken attaches token as the access_token query parameter, for the
+// OAuth endpoints that take it there.
+func queryToken(req *http.Request, token string) {
+	q := req.URL.Query()
+	q.Set("access_token", token)
+	req.URL.RawQuery = q.Encode()
+}
+
+// reauth reports whether err means the API rejected token and, if so, asks
+// the provider to discard it so that the next attempt fetches a new one.
+func reauth(tokens TokenProvider, token string, err error) bool {
+	inv, ok := tokens.(TokenInvalidator)
+	if !ok || token == "" || !IsAuthError(err) {
+		return false
+	}
+	inv.Invalidate(token)
+	return true
+}
+
+// advertiserIDOf extracts the advertiser a request acts on: the advertiser_id
+// query parameter, the first of advertiser_ids, or the advertiser_id field of a
+// JSON body. It returns 0 when the request names no advertiser.
+func advertiserIDOf(req *http.Request) int64 {
+	q := req.URL.Query()
+	if id, err := strconv.ParseInt(q.Get("advertiser_id"), 10, 64); err == nil {
+		return id
+	}
+	var ids []int64
+	if json.Unmarshal([]byte(q.Get("advertiser_ids")), &ids) == nil && len(ids) > 0 {
+		return ids[0]
+	}
+	if req.GetBody == nil {
+		return 0
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return 0
+	}
+	defer body.Close()
+	var v struct {
+		AdvertiserID int64 `json:"advertiser_id"`
+	}
+	_ = json.NewDecoder(body).Decode(&v)
+	return v.AdvertiserID
+}
+
+// requestIDsKey is the context key of the collector installed by
+// WithRequestIDs.
+type requestIDsKey struct{}
+
+type requestIDs struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+// WithRequestIDs returns a context that collects the request_id of every Ocean
+// Engine response received under it, failed ones included, and a function
+// that returns the IDs collected so far, in order. Ocean Engine support asks
+// for these IDs when investigating a call.
+func WithRequestIDs(ctx context.Context) (context.Context, func() []string) {
+	c := &requestIDs{}
+	return context.WithValue(ctx, requestIDsKey{}, c), func() []string {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return slices.Clone(c.ids)
+	}
+}
+
+func recordRequestID(ctx context.Context, id string) {
+	if c, ok := ctx.Value(requestIDsKey{}).(*requestIDs); ok && id != "" {
+		c.mu.Lock()
+		c.ids = append(c.ids, id)
+		c.mu.Unlock()
+	}
 }
 
 // doRequest executes req, parses the standard Ocean Engine response envelope and
 // unmarshals the data field into out. A non-zero envelope code becomes an
-// *APIError. It performs no authentication, so it is also used for the OAuth
-// endpoints, which authenticate with app credentials in the body.
+// *APIError, and a non-2xx response without an envelope an *HTTPError. It
+// performs no authentication, so it is also used for the OAuth endpoints,
+// which authenticate with app credentials in the body.
 func doRequest(httpClient *http.Client, req *http.Request, out any) error {
 	resp, err := httpClient.Do(req)
 	if err != nil {
@@ -154,8 +328,21 @@ func doRequest(httpClient *http.Client, req *http.Request, out any) error {
 	}
 
 	var env envelope
-	if err := json.Unmarshal(raw, &env); err != nil {
-		return fmt.Errorf("oceanengine: decode response (http %d): %w", resp.StatusCode, err)
+	decodeErr := json.Unmarshal(raw, &env)
+	if decodeErr == nil {
+		recordRequestID(req.Context(), env.RequestID)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if decodeErr == nil && env.Code != 0 {
+			return &APIError{Code: env.Code, Message: env.Message, RequestID: env.RequestID}
+		}
+		if len(raw) > 256 {
+			raw = raw[:256]
+		}
+		return &HTTPError{StatusCode: resp.StatusCode, Body: string(raw)}
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("oceanengine: decode response (http %d): %w", resp.StatusCode, decodeErr)
 	}
 	if env.Code != 0 {
 		return &APIError{Code: env.Code, Message: env.Message, RequestID: env.RequestID}
@@ -3,6 +3,7 @@ package oceanengine
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -47,7 +48,7 @@ func TestListCampaignsPagination(t *testing.T) {
 	defer ts.Close()
 
 	c := NewClient("tok", WithBaseURL(ts.URL))
-	res, err := c.ListCampaigns(context.Background(), 777, 2, 5)
+	res, err := c.ListCampaigns(context.Background(), 777, nil, 2, 5)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -63,7 +64,7 @@ func TestAPIErrorPropagated(t *testing.T) {
 	defer ts.Close()
 
 	c := NewClient("tok", WithBaseURL(ts.URL))
-	_, err := c.ListAds(context.Background(), 1, 1, 10)
+	_, err := c.ListAds(context.Background(), 1, nil, 1, 10)
 	var apiErr *APIError
 	if !errors.As(err, &apiErr) {
 		t.Fatalf("expected *APIError, got %v", err)
@@ -73,6 +74,29 @@ func TestAPIErrorPropagated(t *testing.T) {
 	}
 }
 
+func TestWithRequestIDs(t *testing.T) {
+	var n int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n++
+		if n == 1 {
+			_, _ = w.Write([]byte(`{"code":0,"request_id":"req-1","data":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":40002,"message":"no permission","request_id":"req-2"}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	ctx, ids := WithRequestIDs(context.Background())
+	if _, err := c.UpdateCampaignStatus(ctx, 1, []int64{2}, "disable"); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = c.UpdateCampaignStatus(ctx, 1, []int64{2}, "disable")
+	if got := ids(); len(got) != 2 || got[0] != "req-1" || got[1] != "req-2" {
+		t.Fatalf("request IDs = %v, want [req-1 req-2]", got)
+	}
+}
+
 func TestMissingTokenFailsFast(t *testing.T) {
 	c := NewClient("")
 	_, err := c.GetAdvertiserInfo(context.Background(), []int64{1}, nil)
@@ -89,3 +113,201 @@ func TestNormPageSize(t *testing.T) {
 		}
 	}
 }
+
+func TestListAuthorizedAdvertisers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/open_api/oauth2/advertiser/get/" || q.Get("app_id") != "9" || q.Get("secret") != "sec" {
+			t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[
+			{"advertiser_id":1,"advertiser_name":"a-` + q.Get("access_token") + `","account_role":"ADVERTISER","is_valid":true}]}}`))
+	}))
+	defer ts.Close()
+
+	reg := NewTokenRegistry()
+	_ = reg.Register(Account{User: "alice", Tokens: StaticToken("ta")})
+	_ = reg.Register(Account{User: "bob", Tokens: StaticToken("tb")})
+	c := NewClient("", WithBaseURL(ts.URL), WithTokenProvider(reg), WithAppCredentials(9, "sec"))
+	res, err := c.ListAuthorizedAdvertisers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].AdvertiserName != "a-ta" || res[1].User != "bob" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	if _, err := NewClient("tok", WithBaseURL(ts.URL)).ListAuthorizedAdvertisers(context.Background()); err == nil {
+		t.Fatal("expected an error without app credentials")
+	}
+}
+
+func TestListAgentAdvertisersLeavesRegistry(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			_, _ = w.Write([]byte(`{"code":0,"data":{"list":[11,12],"page_info":{"page":1,"total_page":2}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[13],"page_info":{"page":2,"total_page":2}}}`))
+	}))
+	defer ts.Close()
+
+	reg := NewTokenRegistry()
+	_ = reg.Register(Account{User: "agency", AdvertiserIDs: []int64{10}, Tokens: StaticToken("t")})
+	c := NewClient("", WithBaseURL(ts.URL), WithTokenProvider(reg))
+	ids, err := c.ListAgentAdvertisers(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[2] != 13 {
+		t.Fatalf("ids = %v", ids)
+	}
+	// Listing is a plain read; mapping the children is up to the caller.
+	if _, err := reg.TokenFor(context.Background(), 13); err == nil {
+		t.Fatal("listing children should not add them to the registry")
+	}
+}
+
+func TestListFiltering(t *testing.T) {
+	var got []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Query().Get("filtering"))
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[]}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	ctx := context.Background()
+	if _, err := c.ListCampaigns(ctx, 1, &CampaignFilter{IDs: []int64{5}, Status: "CAMPAIGN_STATUS_DISABLE"}, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListAds(ctx, 1, &AdFilter{CampaignID: 9, Name: "618"}, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListAds(ctx, 1, &AdFilter{}, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`{"ids":[5],"status":"CAMPAIGN_STATUS_DISABLE"}`,
+		`{"ad_name":"618","campaign_id":9}`,
+		``, // an empty filter sends no filtering parameter
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d: filtering = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUpdateAdBodies(t *testing.T) {
+	got := map[string]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("%s: method %s", r.URL.Path, r.Method)
+		}
+		b, _ := io.ReadAll(r.Body)
+		got[r.URL.Path] = string(b)
+		_, _ = w.Write([]byte(`{"code":0,"data":{}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	ctx := context.Background()
+	if _, err := c.UpdateAdStatus(ctx, 1, []int64{2, 3}, "disable"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateAdBudget(ctx, 1, []AdBudget{{AdID: 2, Budget: 300}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateAdBid(ctx, 1, []AdBid{{AdID: 2, Bid: 1.5}, {AdID: 3, Bid: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"/open_api/2/ad/update/status/": `{"ad_ids":[2,3],"advertiser_id":1,"opt_status":"disable"}`,
+		"/open_api/2/ad/update/budget/": `{"advertiser_id":1,"data":[{"ad_id":2,"budget":300}]}`,
+		"/open_api/2/ad/update/bid/":    `{"advertiser_id":1,"data":[{"ad_id":2,"bid":1.5},{"ad_id":3,"bid":2}]}`,
+	}
+	for path, body := range want {
+		if got[path] != body {
+			t.Errorf("%s body = %s, want %s", path, got[path], body)
+		}
+	}
+}
+
+func TestListCreatives(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open_api/2/creative/get/" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("filtering"); got != `{"ad_id":7,"status":"CREATIVE_STATUS_AUDIT_DENY"}` {
+			t.Errorf("filtering = %s", got)
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"creative_id":70,"ad_id":7,"title":"T","image_mode":"CREATIVE_IMAGE_MODE_VIDEO",
+			"video_id":"v0200","image_id":"cover","status":"CREATIVE_STATUS_AUDIT_DENY","audit_reject_reason":"misleading claim"}],
+			"page_info":{"page":1,"page_size":10,"total_number":1,"total_page":1}}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	res, err := c.ListCreatives(context.Background(), 1, &CreativeFilter{AdID: 7, Status: "CREATIVE_STATUS_AUDIT_DENY"}, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr := res.List[0]; cr.ID != 70 || cr.VideoID != "v0200" || cr.ImageID != "cover" || cr.AuditRejectReason != "misleading claim" {
+		t.Fatalf("creative = %+v", cr)
+	}
+}
+
+func TestGetCreativeDetail(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open_api/2/creative/read_v2/" || r.URL.Query().Get("ad_id") != "7" {
+			t.Errorf("url = %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"ad_id":7,"advertiser_id":1,"inventory_type":["INVENTORY_FEED"],"source":"Brand",
+			"creatives":[{"creative_id":70,"title":"T","image_mode":"CREATIVE_IMAGE_MODE_LARGE","image_ids":["img1","img2"]}]}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	d, err := c.GetCreativeDetail(context.Background(), 1, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.AdID != 7 || d.Source != "Brand" || len(d.Creatives) != 1 || len(d.Creatives[0].ImageIDs) != 2 {
+		t.Fatalf("detail = %+v", d)
+	}
+}
+
+func TestGetReportLevels(t *testing.T) {
+	var path, fields string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, fields = r.URL.Path, r.URL.Query().Get("fields")
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"cost":1.5}],"page_info":{"page":1,"total_page":1}}}`))
+	}))
+	defer ts.Close()
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	ctx := context.Background()
+
+	if _, err := c.GetReport(ctx, ReportRequest{AdvertiserID: 1, Level: ReportCampaign}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/open_api/2/report/campaign/get/" || fields != jsonParam(ReportCampaign.DefaultFields()) {
+		t.Errorf("campaign: path = %s, fields = %s", path, fields)
+	}
+
+	if _, err := c.GetReport(ctx, ReportRequest{AdvertiserID: 1, Level: ReportCreative, Fields: []string{"cost"}}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/open_api/2/report/creative/get/" || fields != `["cost"]` {
+		t.Errorf("creative: path = %s, fields = %s", path, fields)
+	}
+
+	if _, err := c.GetReport(ctx, ReportRequest{AdvertiserID: 1}); err != nil || path != "/open_api/2/report/ad/get/" {
+		t.Errorf("default level: path = %s, err = %v", path, err)
+	}
+
+	path = ""
+	if _, err := c.GetReport(ctx, ReportRequest{AdvertiserID: 1, Level: "keyword"}); err == nil || path != "" {
+		t.Errorf("unknown level: err = %v, path = %s", err, path)
+	}
+}
@@ -2,7 +2,10 @@ package oceanengine
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 )
 
@@ -46,6 +49,125 @@ func (c *Client) GetAdvertiserInfo(ctx context.Context, advertiserIDs []int64, f
 	return out, nil
 }
 
+// ---------------------------------------------------------------------------
+// Authorized accounts
+// ---------------------------------------------------------------------------
+
+// Account roles reported by oauth2/advertiser/get/.
+const (
+	RoleAdvertiser    = "ADVERTISER"     // 普通广告主
+	RoleCustomerAdmin = "CUSTOMER_ADMIN" // 账号管家 (majordomo)
+	RoleAgent         = "AGENT"          // 一级代理商
+	RoleChildAgent    = "CHILD_AGENT"    // 二级代理商
+)
+
+// AuthorizedAdvertiser is an account the authorizing user granted the app
+// access to.
+type AuthorizedAdvertiser struct {
+	AdvertiserID   int64  `json:"advertiser_id"`
+	AdvertiserName string `json:"advertiser_name"`
+	AccountRole    string `json:"account_role"`
+	IsValid        bool   `json:"is_valid"`
+	// User is the registry account whose token authorized it; empty unless
+	// the client uses a TokenRegistry.
+	User string `json:"user,omitempty"`
+}
+
+// ListAuthorizedAdvertisers returns the accounts the app is authorized for. With
+// a TokenRegistry it lists every registered user's accounts, tagged with the
+// user; pass them to AddAdvertisers to map any the registry did not know yet.
+// It requires the app credentials (WithAppCredentials).
+//
+// GET /open_api/oauth2/advertiser/get/
+func (c *Client) ListAuthorizedAdvertisers(ctx context.Context) ([]AuthorizedAdvertiser, error) {
+	if c.appID == 0 || c.appSecret == "" {
+		return nil, fmt.Errorf("oceanengine: listing authorized advertisers requires app credentials")
+	}
+	accounts := []Account{{Tokens: c.tokens}}
+	if reg, ok := c.tokens.(*TokenRegistry); ok {
+		accounts = reg.Accounts()
+	}
+
+	var out []AuthorizedAdvertiser
+	for _, a := range accounts {
+		// This OAuth endpoint takes each account's token and the app
+		// credentials as query parameters rather than the Access-Token header.
+		q := url.Values{}
+		q.Set("app_id", strconv.FormatUint(c.appID, 10))
+		q.Set("secret", c.appSecret)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/open_api/oauth2/advertiser/get/?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var data struct {
+			List []AuthorizedAdvertiser `json:"list"`
+		}
+		if err := c.send(req, a.Tokens, queryToken, &data); err != nil {
+			return nil, err
+		}
+		for _, adv := range data.List {
+			adv.User = a.User
+			out = append(out, adv)
+		}
+	}
+	return out, nil
+}
+
+// ChildAdvertiser is an advertiser managed by a majordomo or agency account.
+type ChildAdvertiser struct {
+	AdvertiserID   int64  `json:"advertiser_id"`
+	AdvertiserName string `json:"advertiser_name,omitempty"`
+}
+
+// ListMajordomoAdvertisers returns the advertisers under a majordomo (账号管家)
+// account. With a TokenRegistry, pass them to AddChildAdvertisers to make them
+// reachable with the majordomo's token.
+//
+// GET /open_api/2/majordomo/advertiser/select/
+func (c *Client) ListMajordomoAdvertisers(ctx context.Context, majordomoID int64) ([]ChildAdvertiser, error) {
+	q := url.Values{}
+	q.Set("advertiser_id", strconv.FormatInt(majordomoID, 10))
+
+	var out struct {
+		List []ChildAdvertiser `json:"list"`
+	}
+	if err := c.get(ctx, "/open_api/2/majordomo/advertiser/select/", q, &out); err != nil {
+		return nil, err
+	}
+	return out.List, nil
+}
+
+// ListAgentAdvertisers returns the IDs of all advertisers under an agency
+// (代理商) account, walking every page. With a TokenRegistry, pass them to
+// AddChildAdvertisers to make them reachable with the agency's token.
+//
+// GET /open_api/2/agent/advertiser/select/
+func (c *Client) ListAgentAdvertisers(ctx context.Context, agentID int64) ([]int64, error) {
+	pages := paginate(ctx, 0, func(page int) ([]int64, PageInfo, error) {
+		q := url.Values{}
+		q.Set("advertiser_id", strconv.FormatInt(agentID, 10))
+		q.Set("page", strconv.Itoa(page))
+		q.Set("page_size", strconv.Itoa(maxPageSize))
+
+		var out struct {
+			List     []int64  `json:"list"`
+			PageInfo PageInfo `json:"page_info"`
+		}
+		if err := c.get(ctx, "/open_api/2/agent/advertiser/select/", q, &out); err != nil {
+			return nil, PageInfo{}, err
+		}
+		return out.List, out.PageInfo, nil
+	})
+	var ids []int64
+	for id, err := range pages {
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // ---------------------------------------------------------------------------
 // Campaigns (广告组)
 // ---------------------------------------------------------------------------
@@ -60,6 +182,18 @@ type Campaign struct {
 	LandingType  string  `json:"landing_type"`
 	Status       string  `json:"status"`
 	OptStatus    string  `json:"opt_status"`
+	CreateTime   string  `json:"campaign_create_time,omitempty"`
+	ModifyTime   string  `json:"campaign_modify_time,omitempty"`
+}
+
+// CampaignFilter narrows /2/campaign/get/ results. It is sent as the
+// filtering parameter; zero fields are omitted.
+type CampaignFilter struct {
+	IDs         []int64 `json:"ids,omitempty"`
+	Name        string  `json:"campaign_name,omitempty"`        // fuzzy match
+	Status      string  `json:"status,omitempty"`               // e.g. CAMPAIGN_STATUS_ENABLE, CAMPAIGN_STATUS_DISABLE
+	LandingType string  `json:"landing_type,omitempty"`         // e.g. LINK, APP, SHOP
+	CreateTime  string  `json:"campaign_create_time,omitempty"` // YYYY-MM-DD
 }
 
 // CampaignList is the data payload of /2/campaign/get/.
@@ -68,12 +202,14 @@ type CampaignList struct {
 	PageInfo PageInfo   `json:"page_info"`
 }
 
-// ListCampaigns returns campaigns for an advertiser, paginated.
+// ListCampaigns returns campaigns for an advertiser, paginated. filter may be
+// nil.
 //
 // GET /open_api/2/campaign/get/
-func (c *Client) ListCampaigns(ctx context.Context, advertiserID int64, page, pageSize int) (*CampaignList, error) {
+func (c *Client) ListCampaigns(ctx context.Context, advertiserID int64, filter *CampaignFilter, page, pageSize int) (*CampaignList, error) {
 	q := url.Values{}
 	q.Set("advertiser_id", strconv.FormatInt(advertiserID, 10))
+	setFiltering(q, filter)
 	q.Set("page", strconv.Itoa(normPage(page)))
 	q.Set("page_size", strconv.Itoa(normPageSize(pageSize)))
 
@@ -84,6 +220,22 @@ func (c *Client) ListCampaigns(ctx context.Context, advertiserID int64, page, pa
 	return &out, nil
 }
 
+// GetCampaigns returns the given campaigns of an advertiser. IDs that do not
+// exist are left out of the result.
+//
+// GET /open_api/2/campaign/get/
+func (c *Client) GetCampaigns(ctx context.Context, advertiserID int64, ids []int64) ([]Campaign, error) {
+	var out []Campaign
+	for chunk := range slices.Chunk(ids, maxPageSize) {
+		res, err := c.ListCampaigns(ctx, advertiserID, &CampaignFilter{IDs: chunk}, 1, len(chunk))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res.List...)
+	}
+	return out, nil
+}
+
 // ---------------------------------------------------------------------------
 // Ads (广告计划)
 // ---------------------------------------------------------------------------
@@ -96,8 +248,24 @@ type Ad struct {
 	AdvertiserID int64   `json:"advertiser_id"`
 	Budget       float64 `json:"budget"`
 	BudgetMode   string  `json:"budget_mode"`
+	Pricing      string  `json:"pricing,omitempty"` // e.g. PRICING_CPC, PRICING_OCPM
+	Bid          float64 `json:"bid,omitempty"`
+	CpaBid       float64 `json:"cpa_bid,omitempty"` // target conversion bid of oCPM/oCPC ads
 	Status       string  `json:"status"`
 	OptStatus    string  `json:"opt_status"`
+	CreateTime   string  `json:"ad_create_time,omitempty"`
+	ModifyTime   string  `json:"ad_modify_time,omitempty"`
+}
+
+// AdFilter narrows /2/ad/get/ results. It is sent as the filtering parameter;
+// zero fields are omitted.
+type AdFilter struct {
+	IDs        []int64 `json:"ids,omitempty"`
+	Name       string  `json:"ad_name,omitempty"` // fuzzy match
+	Status     string  `json:"status,omitempty"`  // e.g. AD_STATUS_DELIVERY_OK, AD_STATUS_DISABLE
+	CampaignID int64   `json:"campaign_id,omitempty"`
+	CreateTime string  `json:"ad_create_time,omitempty"` // YYYY-MM-DD
+	ModifyTime string  `json:"ad_modify_time,omitempty"` // YYYY-MM-DD HH
 }
 
 // AdList is the data payload of /2/ad/get/.
@@ -106,12 +274,13 @@ type AdList struct {
 	PageInfo PageInfo `json:"page_info"`
 }
 
-// ListAds returns ads for an advertiser, paginated.
+// ListAds returns ads for an advertiser, paginated. filter may be nil.
 //
 // GET /open_api/2/ad/get/
-func (c *Client) ListAds(ctx context.Context, advertiserID int64, page, pageSize int) (*AdList, error) {
+func (c *Client) ListAds(ctx context.Context, advertiserID int64, filter *AdFilter, page, pageSize int) (*AdList, error) {
 	q := url.Values{}
 	q.Set("advertiser_id", strconv.FormatInt(advertiserID, 10))
+	setFiltering(q, filter)
 	q.Set("page", strconv.Itoa(normPage(page)))
 	q.Set("page_size", strconv.Itoa(normPageSize(pageSize)))
 
@@ -122,32 +291,208 @@ func (c *Client) ListAds(ctx context.Context, advertiserID int64, page, pageSize
 	return &out, nil
 }
 
+// GetAds returns the given ads of an advertiser. IDs that do not exist are
+// left out of the result.
+//
+// GET /open_api/2/ad/get/
+func (c *Client) GetAds(ctx context.Context, advertiserID int64, ids []int64) ([]Ad, error) {
+	var out []Ad
+	for chunk := range slices.Chunk(ids, maxPageSize) {
+		res, err := c.ListAds(ctx, advertiserID, &AdFilter{IDs: chunk}, 1, len(chunk))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res.List...)
+	}
+	return out, nil
+}
+
+// ---------------------------------------------------------------------------
+// Creatives (创意)
+// ---------------------------------------------------------------------------
+
+// Creative is a subset of the fields returned by /2/creative/get/, and of
+// each creative in /2/creative/read_v2/.
+type Creative struct {
+	ID                int64    `json:"creative_id"`
+	AdID              int64    `json:"ad_id"`
+	AdvertiserID      int64    `json:"advertiser_id"`
+	Title             string   `json:"title"`
+	ImageMode         string   `json:"image_mode"` // e.g. CREATIVE_IMAGE_MODE_VIDEO, CREATIVE_IMAGE_MODE_LARGE
+	ImageIDs          []string `json:"image_ids,omitempty"`
+	ImageID           string   `json:"image_id,omitempty"` // cover of a video creative
+	VideoID           string   `json:"video_id,omitempty"`
+	Status            string   `json:"status"` // e.g. CREATIVE_STATUS_DELIVERY_OK, CREATIVE_STATUS_AUDIT_DENY
+	OptStatus         string   `json:"opt_status,omitempty"`
+	AuditRejectReason string   `json:"audit_reject_reason,omitempty"`
+	CreateTime        string   `json:"creative_create_time,omitempty"`
+	ModifyTime        string   `json:"creative_modify_time,omitempty"`
+}
+
+// CreativeFilter narrows /2/creative/get/ results. It is sent as the
+// filtering parameter; zero fields are omitted.
+type CreativeFilter struct {
+	IDs        []int64 `json:"ids,omitempty"`
+	AdID       int64   `json:"ad_id,omitempty"`
+	CampaignID int64   `json:"campaign_id,omitempty"`
+	Status     string  `json:"status,omitempty"`               // e.g. CREATIVE_STATUS_DELIVERY_OK, CREATIVE_STATUS_AUDIT_DENY
+	ImageMode  string  `json:"image_mode,omitempty"`           // e.g. CREATIVE_IMAGE_MODE_VIDEO
+	CreateTime string  `json:"creative_create_time,omitempty"` // YYYY-MM-DD
+	ModifyTime string  `json:"creative_modify_time,omitempty"` // YYYY-MM-DD HH
+}
+
+// CreativeList is the data payload of /2/creative/get/.
+type CreativeList struct {
+	List     []Creative `json:"list"`
+	PageInfo PageInfo   `json:"page_info"`
+}
+
+// ListCreatives returns creatives for an advertiser, paginated. filter may be
+// nil.
+//
+// GET /open_api/2/creative/get/
+func (c *Client) ListCreatives(ctx context.Context, advertiserID int64, filter *CreativeFilter, page, pageSize int) (*CreativeList, error) {
+	q := url.Values{}
+	q.Set("advertiser_id", strconv.FormatInt(advertiserID, 10))
+	setFiltering(q, filter)
+	q.Set("page", strconv.Itoa(normPage(page)))
+	q.Set("page_size", strconv.Itoa(normPageSize(pageSize)))
+
+	var out CreativeList
+	if err := c.get(ctx, "/open_api/2/creative/get/", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreativeDetail is a subset of the data payload of /2/creative/read_v2/: an
+// ad's creative settings and all of its creatives.
+type CreativeDetail struct {
+	AdID                 int64      `json:"ad_id"`
+	AdvertiserID         int64      `json:"advertiser_id"`
+	CreativeMaterialMode string     `json:"creative_material_mode,omitempty"` // STATIC_ASSEMBLE for programmatic creatives, empty for custom ones
+	InventoryType        []string   `json:"inventory_type,omitempty"`         // placements, e.g. INVENTORY_FEED, INVENTORY_AWEME_FEED
+	Source               string     `json:"source,omitempty"`                 // brand or app name shown with the creatives
+	Creatives            []Creative `json:"creatives"`
+}
+
+// GetCreativeDetail returns the full creative details of one ad.
+//
+// GET /open_api/2/creative/read_v2/
+func (c *Client) GetCreativeDetail(ctx context.Context, advertiserID, adID int64) (*CreativeDetail, error) {
+	q := url.Values{}
+	q.Set("advertiser_id", strconv.FormatInt(advertiserID, 10))
+	q.Set("ad_id", strconv.FormatInt(adID, 10))
+
+	var out CreativeDetail
+	if err := c.get(ctx, "/open_api/2/creative/read_v2/", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // ---------------------------------------------------------------------------
 // Reporting
 // ---------------------------------------------------------------------------
 
+// ReportLevel is what each row of a report describes, and with it the
+// endpoint GetReport queries.
+type ReportLevel string
+
+const (
+	ReportAdvertiser ReportLevel = "advertiser"
+	ReportCampaign   ReportLevel = "campaign"
+	ReportAd         ReportLevel = "ad"
+	ReportCreative   ReportLevel = "creative"
+)
+
+// ReportLevels lists the levels in order of detail.
+var ReportLevels = []ReportLevel{ReportAdvertiser, ReportCampaign, ReportAd, ReportCreative}
+
+// baseReportFields are the metrics every report level returns by default.
+var baseReportFields = []string{"cost", "show", "click", "ctr", "avg_click_cost", "convert", "convert_cost", "convert_rate"}
+
+type reportLevel struct {
+	path    string
+	groupBy []string // the group_by dimensions the endpoint accepts
+	fields  []string // default metrics
+}
+
+var reportLevels = map[ReportLevel]reportLevel{
+	ReportAdvertiser: {
+		path:    "/open_api/2/report/advertiser/get/",
+		groupBy: []string{"STAT_GROUP_BY_FIELD_STAT_TIME"},
+		fields:  baseReportFields,
+	},
+	ReportCampaign: {
+		path:    "/open_api/2/report/campaign/get/",
+		groupBy: []string{"STAT_GROUP_BY_FIELD_ID", "STAT_GROUP_BY_FIELD_STAT_TIME"},
+		fields:  baseReportFields,
+	},
+	ReportAd: {
+		path: "/open_api/2/report/ad/get/",
+		groupBy: []string{"STAT_GROUP_BY_FIELD_ID", "STAT_GROUP_BY_FIELD_STAT_TIME", "STAT_GROUP_BY_CAMPAIGN_ID",
+			"STAT_GROUP_BY_PRICING", "STAT_GROUP_BY_IMAGE_MODE", "STAT_GROUP_BY_INVENTORY"},
+		fields: append(slices.Clone(baseReportFields), "deep_convert", "deep_convert_cost"),
+	},
+	ReportCreative: {
+		path: "/open_api/2/report/creative/get/",
+		groupBy: []string{"STAT_GROUP_BY_FIELD_ID", "STAT_GROUP_BY_FIELD_STAT_TIME", "STAT_GROUP_BY_CAMPAIGN_ID",
+			"STAT_GROUP_BY_AD_ID", "STAT_GROUP_BY_PRICING", "STAT_GROUP_BY_IMAGE_MODE", "STAT_GROUP_BY_INVENTORY"},
+		fields: append(slices.Clone(baseReportFields), "total_play", "valid_play", "play_over_rate"),
+	},
+}
+
+// GroupBy returns the group_by dimensions reports of level l accept, or nil
+// if l is not a known level.
+func (l ReportLevel) GroupBy() []string {
+	return slices.Clone(reportLevels[l].groupBy)
+}
+
+// DefaultFields returns the metrics GetReport requests for level l when the
+// request names none.
+func (l ReportLevel) DefaultFields() []string {
+	return slices.Clone(reportLevels[l].fields)
+}
+
 // ReportRequest describes a performance report query.
 type ReportRequest struct {
 	AdvertiserID int64
-	StartDate    string // YYYY-MM-DD
-	EndDate      string // YYYY-MM-DD
+	Level        ReportLevel // defaults to ReportAd
+	StartDate    string      // YYYY-MM-DD
+	EndDate      string      // YYYY-MM-DD
 	GroupBy      []string
-	Fields       []string
+	Fields       []string // defaults to Level.DefaultFields()
 	Page         int
 	PageSize     int
 }
 
-// ReportResult is the data payload of the report endpoint. Rows are left
+// ReportResult is the data payload of the report endpoints. Rows are left
 // untyped because the available metrics depend on the requested fields.
 type ReportResult struct {
 	List     []map[string]any `json:"list"`
 	PageInfo PageInfo         `json:"page_info"`
 }
 
-// GetReport returns an ad-level performance report.
+// GetReport returns a performance report at the request's level.
 //
+// GET /open_api/2/report/advertiser/get/
+// GET /open_api/2/report/campaign/get/
 // GET /open_api/2/report/ad/get/
+// GET /open_api/2/report/creative/get/
 func (c *Client) GetReport(ctx context.Context, req ReportRequest) (*ReportResult, error) {
+	if req.Level == "" {
+		req.Level = ReportAd
+	}
+	level, ok := reportLevels[req.Level]
+	if !ok {
+		return nil, fmt.Errorf("oceanengine: unknown report level %q", req.Level)
+	}
+	fields := req.Fields
+	if len(fields) == 0 {
+		fields = level.fields
+	}
+
 	q := url.Values{}
 	q.Set("advertiser_id", strconv.FormatInt(req.AdvertiserID, 10))
 	q.Set("start_date", req.StartDate)
@@ -155,14 +500,12 @@ func (c *Client) GetReport(ctx context.Context, req ReportRequest) (*ReportResul
 	if len(req.GroupBy) > 0 {
 		q.Set("group_by", jsonParam(req.GroupBy))
 	}
-	if len(req.Fields) > 0 {
-		q.Set("fields", jsonParam(req.Fields))
-	}
+	q.Set("fields", jsonParam(fields))
 	q.Set("page", strconv.Itoa(normPage(req.Page)))
 	q.Set("page_size", strconv.Itoa(normPageSize(req.PageSize)))
 
 	var out ReportResult
-	if err := c.get(ctx, "/open_api/2/report/ad/get/", q, &out); err != nil {
+	if err := c.get(ctx, level.path, q, &out); err != nil {
 		return nil, err
 	}
 	return &out, nil
@@ -173,23 +516,24 @@ func (c *Client) GetReport(ctx context.Context, req ReportRequest) (*ReportResul
 // ---------------------------------------------------------------------------
 
 // UpdateCampaignStatus enables, disables or deletes campaigns. optStatus is one
-// of "enable", "disable" or "delete".
+// of "enable", "disable" or "delete". The result lists the campaigns Ocean
+// Engine updated and why it refused the others.
 //
 // POST /open_api/2/campaign/update/status/
-func (c *Client) UpdateCampaignStatus(ctx context.Context, advertiserID int64, campaignIDs []int64, optStatus string) error {
+func (c *Client) UpdateCampaignStatus(ctx context.Context, advertiserID int64, campaignIDs []int64, optStatus string) (*BatchResult, error) {
 	body := map[string]any{
 		"advertiser_id": advertiserID,
 		"campaign_ids":  campaignIDs,
 		"opt_status":    optStatus,
 	}
-	return c.post(ctx, "/open_api/2/campaign/update/status/", body, nil)
+	return c.postBatchBody(ctx, "/open_api/2/campaign/update/status/", body, "campaign_id", campaignIDs)
 }
 
 // UpdateCampaignBudget sets a new daily budget for a campaign. budgetMode is
 // typically "BUDGET_MODE_DAY".
 //
 // POST /open_api/2/campaign/update/budget/
-func (c *Client) UpdateCampaignBudget(ctx context.Context, advertiserID, campaignID int64, budget float64, budgetMode string) error {
+func (c *Client) UpdateCampaignBudget(ctx context.Context, advertiserID, campaignID int64, budget float64, budgetMode string) (*BatchResult, error) {
 	body := map[string]any{
 		"advertiser_id": advertiserID,
 		"data": []map[string]any{{
@@ -198,7 +542,66 @@ func (c *Client) UpdateCampaignBudget(ctx context.Context, advertiserID, campaig
 			"budget_mode": budgetMode,
 		}},
 	}
-	return c.post(ctx, "/open_api/2/campaign/update/budget/", body, nil)
+	return c.postBatchBody(ctx, "/open_api/2/campaign/update/budget/", body, "campaign_id", []int64{campaignID})
+}
+
+// UpdateAdStatus enables, disables or deletes ads. optStatus is one of
+// "enable", "disable" or "delete".
+//
+// POST /open_api/2/ad/update/status/
+func (c *Client) UpdateAdStatus(ctx context.Context, advertiserID int64, adIDs []int64, optStatus string) (*BatchResult, error) {
+	body := map[string]any{
+		"advertiser_id": advertiserID,
+		"ad_ids":        adIDs,
+		"opt_status":    optStatus,
+	}
+	return c.postBatchBody(ctx, "/open_api/2/ad/update/status/", body, "ad_id", adIDs)
+}
+
+// AdBudget is a new budget for one ad. The ad keeps its budget mode.
+type AdBudget struct {
+	AdID   int64   `json:"ad_id"`
+	Budget float64 `json:"budget"`
+}
+
+// UpdateAdBudget sets new budgets for one or more ads.
+//
+// POST /open_api/2/ad/update/budget/
+func (c *Client) UpdateAdBudget(ctx context.Context, advertiserID int64, budgets []AdBudget) (*BatchResult, error) {
+	ids := make([]int64, len(budgets))
+	for i, b := range budgets {
+		ids[i] = b.AdID
+	}
+	return c.postBatch(ctx, "/open_api/2/ad/update/budget/", advertiserID, budgets, "ad_id", ids)
+}
+
+// AdBid is a new bid for one ad. For oCPM/oCPC ads it is the target
+// conversion bid (cpa_bid).
+type AdBid struct {
+	AdID int64   `json:"ad_id"`
+	Bid  float64 `json:"bid"`
+}
+
+// UpdateAdBid sets new bids for one or more ads.
+//
+// POST /open_api/2/ad/update/bid/
+func (c *Client) UpdateAdBid(ctx context.Context, advertiserID int64, bids []AdBid) (*BatchResult, error) {
+	ids := make([]int64, len(bids))
+	for i, b := range bids {
+		ids[i] = b.AdID
+	}
+	return c.postBatch(ctx, "/open_api/2/ad/update/bid/", advertiserID, bids, "ad_id", ids)
+}
+
+// setFiltering adds filter as the JSON filtering parameter, unless it is nil or
+// has no fields set.
+func setFiltering[F any](q url.Values, filter *F) {
+	if filter == nil {
+		return
+	}
+	if f := jsonParam(filter); f != "{}" {
+		q.Set("filtering", f)
+	}
 }
 
 func normPage(p int) int {
@@ -212,8 +615,8 @@ func normPageSize(s int) int {
 	if s <= 0 {
 		return 10
 	}
-	if s > 100 {
-		return 100
+	if s > maxPageSize {
+		return maxPageSize
 	}
 	return s
 }
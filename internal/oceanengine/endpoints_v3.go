@@ -0,0 +1,253 @@
+package oceanengine
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+// Accounts upgraded to 巨量广告 (Ocean Engine ads 2.0 / 体验版) manage ads as
+// projects (项目) and promotions (广告, also called 单元) through the
+// /open_api/v3.0/ endpoints below, instead of campaigns and ads.
+
+// ---------------------------------------------------------------------------
+// Projects (项目)
+// ---------------------------------------------------------------------------
+
+// ProjectDeliverySetting is the budget and bid part of a project's delivery
+// settings.
+type ProjectDeliverySetting struct {
+	Budget     float64 `json:"budget"`
+	BudgetMode string  `json:"budget_mode"`
+	Bid        float64 `json:"bid,omitempty"`
+	CpaBid     float64 `json:"cpa_bid,omitempty"`
+	DeepCpaBid float64 `json:"deep_cpabid,omitempty"`
+	RoiGoal    float64 `json:"roi_goal,omitempty"`
+}
+
+// Project is a subset of the fields returned by /v3.0/project/list/.
+type Project struct {
+	ID              int64                  `json:"project_id"`
+	Name            string                 `json:"name"`
+	AdvertiserID    int64                  `json:"advertiser_id"`
+	LandingType     string                 `json:"landing_type"`
+	DeliveryMode    string                 `json:"delivery_mode"` // MANUAL or PROCEDURAL
+	AdType          string                 `json:"ad_type,omitempty"`
+	Status          string                 `json:"status"`
+	StatusFirst     string                 `json:"status_first,omitempty"`
+	StatusSecond    []string               `json:"status_second,omitempty"`
+	OptStatus       string                 `json:"opt_status"` // ENABLE or DISABLE
+	DeliverySetting ProjectDeliverySetting `json:"delivery_setting"`
+	CreateTime      string                 `json:"project_create_time,omitempty"`
+	ModifyTime      string                 `json:"project_modify_time,omitempty"`
+}
+
+// ProjectFilter narrows /v3.0/project/list/ results. It is sent as the
+// filtering parameter; zero fields are omitted.
+type ProjectFilter struct {
+	IDs          []int64 `json:"ids,omitempty"`
+	Name         string  `json:"name,omitempty"`          // fuzzy match
+	LandingType  string  `json:"landing_type,omitempty"`  // e.g. LINK, APP, MICRO_GAME
+	DeliveryMode string  `json:"delivery_mode,omitempty"` // MANUAL or PROCEDURAL
+	StatusFirst  string  `json:"status_first,omitempty"`  // e.g. PROJECT_STATUS_ENABLE, PROJECT_STATUS_DISABLE
+}
+
+// ProjectList is the data payload of /v3.0/project/list/.
+type ProjectList struct {
+	List     []Project `json:"list"`
+	PageInfo PageInfo  `json:"page_info"`
+}
+
+// ListProjects returns projects for an advertiser, paginated. filter may be
+// nil.
+//
+// GET /open_api/v3.0/project/list/
+func (c *Client) ListProjects(ctx context.Context, advertiserID int64, filter *ProjectFilter, page, pageSize int) (*ProjectList, error) {
+	q := url.Values{}
+	q.Set("advertiser_id", strconv.FormatInt(advertiserID, 10))
+	setFiltering(q, filter)
+	q.Set("page", strconv.Itoa(normPage(page)))
+	q.Set("page_size", strconv.Itoa(normPageSize(pageSize)))
+
+	var out ProjectList
+	if err := c.get(ctx, "/open_api/v3.0/project/list/", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProjects returns the given projects of an advertiser. IDs that do not
+// exist are left out of the result.
+//
+// GET /open_api/v3.0/project/list/
+func (c *Client) GetProjects(ctx context.Context, advertiserID int64, ids []int64) ([]Project, error) {
+	var out []Project
+	for chunk := range slices.Chunk(ids, maxPageSize) {
+		res, err := c.ListProjects(ctx, advertiserID, &ProjectFilter{IDs: chunk}, 1, len(chunk))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res.List...)
+	}
+	return out, nil
+}
+
+// ---------------------------------------------------------------------------
+// Promotions (广告 / 单元)
+// ---------------------------------------------------------------------------
+
+// Promotion is a subset of the fields returned by /v3.0/promotion/list/.
+type Promotion struct {
+	ID           int64    `json:"promotion_id"`
+	Name         string   `json:"promotion_name"`
+	ProjectID    int64    `json:"project_id"`
+	AdvertiserID int64    `json:"advertiser_id"`
+	Status       string   `json:"status"`
+	StatusFirst  string   `json:"status_first,omitempty"`
+	StatusSecond []string `json:"status_second,omitempty"`
+	OptStatus    string   `json:"opt_status"` // ENABLE or DISABLE
+	Budget       float64  `json:"budget,omitempty"`
+	BudgetMode   string   `json:"budget_mode,omitempty"`
+	Bid          float64  `json:"bid,omitempty"`
+	CpaBid       float64  `json:"cpa_bid,omitempty"`
+	DeepCpaBid   float64  `json:"deep_cpabid,omitempty"`
+	RoiGoal      float64  `json:"roi_goal,omitempty"`
+	CreateTime   string   `json:"promotion_create_time,omitempty"`
+	ModifyTime   string   `json:"promotion_modify_time,omitempty"`
+}
+
+// PromotionFilter narrows /v3.0/promotion/list/ results. It is sent as the
+// filtering parameter; zero fields are omitted.
+type PromotionFilter struct {
+	IDs         []int64 `json:"ids,omitempty"`
+	ProjectID   int64   `json:"project_id,omitempty"`
+	Name        string  `json:"promotion_name,omitempty"` // fuzzy match
+	StatusFirst string  `json:"status_first,omitempty"`   // e.g. PROMOTION_STATUS_ENABLE, PROMOTION_STATUS_DISABLE
+}
+
+// PromotionList is the data payload of /v3.0/promotion/list/.
+type PromotionList struct {
+	List     []Promotion `json:"list"`
+	PageInfo PageInfo    `json:"page_info"`
+}
+
+// ListPromotions returns promotions for an advertiser, paginated. filter may
+// be nil.
+//
+// GET /open_api/v3.0/promotion/list/
+func (c *Client) ListPromotions(ctx context.Context, advertiserID int64, filter *PromotionFilter, page, pageSize int) (*PromotionList, error) {
+	q := url.Values{}
+	q.Set("advertiser_id", strconv.FormatInt(advertiserID, 10))
+	setFiltering(q, filter)
+	q.Set("page", strconv.Itoa(normPage(page)))
+	q.Set("page_size", strconv.Itoa(normPageSize(pageSize)))
+
+	var out PromotionList
+	if err := c.get(ctx, "/open_api/v3.0/promotion/list/", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPromotions returns the given promotions of an advertiser. IDs that do
+// not exist are left out of the result.
+//
+// GET /open_api/v3.0/promotion/list/
+func (c *Client) GetPromotions(ctx context.Context, advertiserID int64, ids []int64) ([]Promotion, error) {
+	var out []Promotion
+	for chunk := range slices.Chunk(ids, maxPageSize) {
+		res, err := c.ListPromotions(ctx, advertiserID, &PromotionFilter{IDs: chunk}, 1, len(chunk))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res.List...)
+	}
+	return out, nil
+}
+
+// ---------------------------------------------------------------------------
+// Project and promotion writes (gated by the server's write flag)
+// ---------------------------------------------------------------------------
+
+// Opt statuses of the v3.0 status endpoints. Deleting is a separate endpoint.
+const (
+	OptStatusEnable  = "ENABLE"
+	OptStatusDisable = "DISABLE"
+)
+
+// UpdateProjectStatus enables or disables projects. optStatus is
+// OptStatusEnable or OptStatusDisable.
+//
+// POST /open_api/v3.0/project/status/update/
+func (c *Client) UpdateProjectStatus(ctx context.Context, advertiserID int64, projectIDs []int64, optStatus string) (*BatchResult, error) {
+	data := make([]map[string]any, len(projectIDs))
+	for i, id := range projectIDs {
+		data[i] = map[string]any{"project_id": id, "opt_status": optStatus}
+	}
+	return c.postBatch(ctx, "/open_api/v3.0/project/status/update/", advertiserID, data, "project_id", projectIDs)
+}
+
+// ProjectBudget is a new budget for one project. The project keeps its budget
+// mode.
+type ProjectBudget struct {
+	ProjectID int64   `json:"project_id"`
+	Budget    float64 `json:"budget"`
+}
+
+// UpdateProjectBudget sets new budgets for one or more projects.
+//
+// POST /open_api/v3.0/project/budget/update/
+func (c *Client) UpdateProjectBudget(ctx context.Context, advertiserID int64, budgets []ProjectBudget) (*BatchResult, error) {
+	ids := make([]int64, len(budgets))
+	for i, b := range budgets {
+		ids[i] = b.ProjectID
+	}
+	return c.postBatch(ctx, "/open_api/v3.0/project/budget/update/", advertiserID, budgets, "project_id", ids)
+}
+
+// UpdatePromotionStatus enables or disables promotions. optStatus is
+// OptStatusEnable or OptStatusDisable.
+//
+// POST /open_api/v3.0/promotion/status/update/
+func (c *Client) UpdatePromotionStatus(ctx context.Context, advertiserID int64, promotionIDs []int64, optStatus string) (*BatchResult, error) {
+	data := make([]map[string]any, len(promotionIDs))
+	for i, id := range promotionIDs {
+		data[i] = map[string]any{"promotion_id": id, "opt_status": optStatus}
+	}
+	return c.postBatch(ctx, "/open_api/v3.0/promotion/status/update/", advertiserID, data, "promotion_id", promotionIDs)
+}
+
+// PromotionBudget is a new budget for one promotion.
+type PromotionBudget struct {
+	PromotionID int64   `json:"promotion_id"`
+	Budget      float64 `json:"budget"`
+}
+
+// UpdatePromotionBudget sets new budgets for one or more promotions.
+//
+// POST /open_api/v3.0/promotion/budget/update/
+func (c *Client) UpdatePromotionBudget(ctx context.Context, advertiserID int64, budgets []PromotionBudget) (*BatchResult, error) {
+	ids := make([]int64, len(budgets))
+	for i, b := range budgets {
+		ids[i] = b.PromotionID
+	}
+	return c.postBatch(ctx, "/open_api/v3.0/promotion/budget/update/", advertiserID, budgets, "promotion_id", ids)
+}
+
+// PromotionBid is a new bid for one promotion.
+type PromotionBid struct {
+	PromotionID int64   `json:"promotion_id"`
+	Bid         float64 `json:"bid"`
+}
+
+// UpdatePromotionBid sets new bids for one or more promotions.
+//
+// POST /open_api/v3.0/promotion/bid/update/
+func (c *Client) UpdatePromotionBid(ctx context.Context, advertiserID int64, bids []PromotionBid) (*BatchResult, error) {
+	ids := make([]int64, len(bids))
+	for i, b := range bids {
+		ids[i] = b.PromotionID
+	}
+	return c.postBatch(ctx, "/open_api/v3.0/promotion/bid/update/", advertiserID, bids, "promotion_id", ids)
+}
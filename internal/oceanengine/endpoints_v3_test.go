@@ -0,0 +1,58 @@
+package oceanengine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListProjects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open_api/v3.0/project/list/" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("filtering"); got != `{"delivery_mode":"MANUAL","status_first":"PROJECT_STATUS_ENABLE"}` {
+			t.Errorf("filtering = %s", got)
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"project_id":11,"name":"P","delivery_mode":"MANUAL","opt_status":"ENABLE",
+			"status_second":["PROJECT_STATUS_BUDGET_EXCEED"],"delivery_setting":{"budget":500,"budget_mode":"BUDGET_MODE_DAY","cpa_bid":30}}],
+			"page_info":{"page":1,"page_size":10,"total_number":1,"total_page":1}}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	res, err := c.ListProjects(context.Background(), 1, &ProjectFilter{DeliveryMode: "MANUAL", StatusFirst: "PROJECT_STATUS_ENABLE"}, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := res.List[0]
+	if p.ID != 11 || p.DeliverySetting.Budget != 500 || p.DeliverySetting.CpaBid != 30 || len(p.StatusSecond) != 1 {
+		t.Fatalf("project = %+v", p)
+	}
+}
+
+func TestGetPromotionsChunksIDs(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/open_api/v3.0/promotion/list/" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"promotion_id":5,"promotion_name":"U","project_id":11,"opt_status":"DISABLE"}],"page_info":{"page":1,"total_page":1}}}`))
+	}))
+	defer ts.Close()
+
+	ids := make([]int64, 150)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	list, err := c.GetPromotions(context.Background(), 1, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || len(list) != 2 || list[0].Name != "U" || list[0].ProjectID != 11 {
+		t.Fatalf("calls = %d, list = %+v", calls, list)
+	}
+}
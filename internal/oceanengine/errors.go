@@ -0,0 +1,94 @@
+package oceanengine
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Documented Ocean Engine envelope codes.
+const (
+	CodeInvalidParameter    = 40001 // 参数错误
+	CodeNoPermission        = 40002 // 没有权限进行相关操作
+	CodeInvalidFilterField  = 40003 // 过滤条件的 field 字段错误
+	CodeNotFound            = 40004 // 资源不存在
+	CodeRateLimited         = 40100 // 请求过于频繁 (app/advertiser QPS limit)
+	CodeAccessTokenExpired  = 40102 // access_token 已过期
+	CodeRefreshTokenExpired = 40103 // refresh_token 已过期
+	CodeAccessTokenEmpty    = 40104 // access_token 为空
+	CodeAccessTokenInvalid  = 40105 // access_token 错误
+	CodeAccountLoginError   = 40106 // 账户登录异常
+	CodeRefreshTokenInvalid = 40107 // refresh_token 错误
+	CodeGrantTypeInvalid    = 40108 // 授权类型错误
+	CodeServiceRateLimited  = 40110 // 请求超过服务整体频控
+	CodeSystemError         = 50000 // 系统错误
+)
+
+// Error categories. An *APIError or *HTTPError matches its category with
+// errors.Is, e.g. errors.Is(err, ErrRateLimited).
+var (
+	ErrTokenExpired     = errors.New("oceanengine: token expired")
+	ErrAuthInvalid      = errors.New("oceanengine: authentication invalid")
+	ErrPermissionDenied = errors.New("oceanengine: permission denied")
+	ErrRateLimited      = errors.New("oceanengine: rate limited")
+	ErrInvalidParameter = errors.New("oceanengine: invalid parameter")
+	ErrNotFound         = errors.New("oceanengine: resource not found")
+	ErrSystem           = errors.New("oceanengine: system error")
+)
+
+// codeCategories maps envelope codes to their error category.
+var codeCategories = map[int]error{
+	CodeInvalidParameter:    ErrInvalidParameter,
+	CodeInvalidFilterField:  ErrInvalidParameter,
+	CodeGrantTypeInvalid:    ErrInvalidParameter,
+	CodeNoPermission:        ErrPermissionDenied,
+	CodeNotFound:            ErrNotFound,
+	CodeRateLimited:         ErrRateLimited,
+	CodeServiceRateLimited:  ErrRateLimited,
+	CodeAccessTokenExpired:  ErrTokenExpired,
+	CodeRefreshTokenExpired: ErrTokenExpired,
+	CodeAccessTokenEmpty:    ErrAuthInvalid,
+	CodeAccessTokenInvalid:  ErrAuthInvalid,
+	CodeAccountLoginError:   ErrAuthInvalid,
+	CodeRefreshTokenInvalid: ErrAuthInvalid,
+	CodeSystemError:         ErrSystem,
+}
+
+// Category returns the error category of the code, or nil if it is not in
+// the catalog.
+func (e *APIError) Category() error { return codeCategories[e.Code] }
+
+// Is reports whether target is the error's category.
+func (e *APIError) Is(target error) bool {
+	c := e.Category()
+	return c != nil && c == target
+}
+
+// Is reports whether target is the category implied by the HTTP status.
+func (e *HTTPError) Is(target error) bool {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case e.StatusCode == http.StatusUnauthorized:
+		return target == ErrAuthInvalid
+	case e.StatusCode == http.StatusForbidden:
+		return target == ErrPermissionDenied
+	case e.StatusCode >= 500:
+		return target == ErrSystem
+	}
+	return false
+}
+
+// IsRateLimited reports whether err is an Ocean Engine QPS-limit rejection.
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// IsTokenExpired reports whether err says the access or refresh token expired.
+func IsTokenExpired(err error) bool { return errors.Is(err, ErrTokenExpired) }
+
+// IsAuthError reports whether err is any authentication failure: an expired,
+// empty or invalid token.
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrAuthInvalid)
+}
+
+// IsPermissionDenied reports whether the token lacks access to the resource.
+func IsPermissionDenied(err error) bool { return errors.Is(err, ErrPermissionDenied) }
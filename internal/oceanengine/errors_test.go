@@ -0,0 +1,55 @@
+package oceanengine
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorCategories(t *testing.T) {
+	cases := []struct {
+		code int
+		want error
+	}{
+		{CodeRateLimited, ErrRateLimited},
+		{CodeServiceRateLimited, ErrRateLimited},
+		{CodeAccessTokenExpired, ErrTokenExpired},
+		{CodeAccessTokenInvalid, ErrAuthInvalid},
+		{CodeNoPermission, ErrPermissionDenied},
+		{CodeInvalidParameter, ErrInvalidParameter},
+		{CodeNotFound, ErrNotFound},
+		{CodeSystemError, ErrSystem},
+	}
+	for _, tc := range cases {
+		// Wrapped, as callers see it after fmt.Errorf("...: %w").
+		err := fmt.Errorf("list ads: %w", &APIError{Code: tc.code})
+		if !errors.Is(err, tc.want) {
+			t.Errorf("code %d: errors.Is(%v) = false", tc.code, tc.want)
+		}
+		if tc.want != ErrSystem && errors.Is(err, ErrSystem) {
+			t.Errorf("code %d unexpectedly matches ErrSystem", tc.code)
+		}
+	}
+	if errors.Is(&APIError{Code: 12345}, ErrSystem) {
+		t.Error("unknown codes must not match any category")
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	if !IsRateLimited(&HTTPError{StatusCode: http.StatusTooManyRequests}) {
+		t.Error("HTTP 429 should be rate limited")
+	}
+	if !IsTokenExpired(&APIError{Code: CodeAccessTokenExpired}) {
+		t.Error("40102 should be token expired")
+	}
+	if !IsAuthError(&APIError{Code: CodeAccessTokenInvalid}) || !IsAuthError(&APIError{Code: CodeAccessTokenExpired}) {
+		t.Error("40102 and 40105 should be auth errors")
+	}
+	if IsAuthError(&APIError{Code: CodeRateLimited}) {
+		t.Error("40100 is not an auth error")
+	}
+	if !IsPermissionDenied(&APIError{Code: CodeNoPermission}) {
+		t.Error("40002 should be permission denied")
+	}
+}
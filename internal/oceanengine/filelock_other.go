@@ -0,0 +1,11 @@
+//go:build !unix
+
+package oceanengine
+
+import "context"
+
+// lockFile is a no-op where advisory file locks are unavailable; processes
+// sharing a token file there must not refresh concurrently.
+func lockFile(context.Context, string) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,117 @@
+package oceanengine
+
+import (
+	"context"
+	"iter"
+)
+
+// maxPageSize is the largest page_size the list endpoints accept.
+const maxPageSize = 100
+
+// paginate walks a paginated endpoint, yielding items until the last page
+// (per PageInfo.TotalPage), until maxItems have been yielded (if > 0), until
+// ctx is done or fetch fails — the error is yielded once — or until the
+// consumer stops. fetch returns the items and page info of one page.
+func paginate[T any](ctx context.Context, maxItems int, fetch func(page int) ([]T, PageInfo, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		n := 0
+		for page := 1; ; page++ {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			items, info, err := fetch(page)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+				if n++; maxItems > 0 && n >= maxItems {
+					return
+				}
+			}
+			if page >= info.TotalPage || len(items) == 0 {
+				return
+			}
+		}
+	}
+}
+
+// AllCampaigns iterates over every campaign of an advertiser that matches
+// filter (which may be nil), fetching pages of the maximum size on demand.
+// maxItems caps the number of campaigns yielded; pass 0 for no cap. Iteration
+// stops at the first error, which is yielded.
+func (c *Client) AllCampaigns(ctx context.Context, advertiserID int64, filter *CampaignFilter, maxItems int) iter.Seq2[Campaign, error] {
+	return paginate(ctx, maxItems, func(page int) ([]Campaign, PageInfo, error) {
+		res, err := c.ListCampaigns(ctx, advertiserID, filter, page, maxPageSize)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return res.List, res.PageInfo, nil
+	})
+}
+
+// AllAds iterates over every ad of an advertiser that matches filter; see
+// AllCampaigns.
+func (c *Client) AllAds(ctx context.Context, advertiserID int64, filter *AdFilter, maxItems int) iter.Seq2[Ad, error] {
+	return paginate(ctx, maxItems, func(page int) ([]Ad, PageInfo, error) {
+		res, err := c.ListAds(ctx, advertiserID, filter, page, maxPageSize)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return res.List, res.PageInfo, nil
+	})
+}
+
+// AllCreatives iterates over every creative of an advertiser that matches
+// filter; see AllCampaigns.
+func (c *Client) AllCreatives(ctx context.Context, advertiserID int64, filter *CreativeFilter, maxItems int) iter.Seq2[Creative, error] {
+	return paginate(ctx, maxItems, func(page int) ([]Creative, PageInfo, error) {
+		res, err := c.ListCreatives(ctx, advertiserID, filter, page, maxPageSize)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return res.List, res.PageInfo, nil
+	})
+}
+
+// AllReportRows iterates over every row of a report; req.Page and
+// req.PageSize are ignored. See AllCampaigns.
+func (c *Client) AllReportRows(ctx context.Context, req ReportRequest, maxItems int) iter.Seq2[map[string]any, error] {
+	return paginate(ctx, maxItems, func(page int) ([]map[string]any, PageInfo, error) {
+		req.Page, req.PageSize = page, maxPageSize
+		res, err := c.GetReport(ctx, req)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return res.List, res.PageInfo, nil
+	})
+}
+
+// AllProjects iterates over every project of an advertiser that matches
+// filter; see AllCampaigns.
+func (c *Client) AllProjects(ctx context.Context, advertiserID int64, filter *ProjectFilter, maxItems int) iter.Seq2[Project, error] {
+	return paginate(ctx, maxItems, func(page int) ([]Project, PageInfo, error) {
+		res, err := c.ListProjects(ctx, advertiserID, filter, page, maxPageSize)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return res.List, res.PageInfo, nil
+	})
+}
+
+// AllPromotions iterates over every promotion of an advertiser that matches
+// filter; see AllCampaigns.
+func (c *Client) AllPromotions(ctx context.Context, advertiserID int64, filter *PromotionFilter, maxItems int) iter.Seq2[Promotion, error] {
+	return paginate(ctx, maxItems, func(page int) ([]Promotion, PageInfo, error) {
+		res, err := c.ListPromotions(ctx, advertiserID, filter, page, maxPageSize)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return res.List, res.PageInfo, nil
+	})
+}
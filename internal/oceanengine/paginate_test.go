@@ -0,0 +1,104 @@
+package oceanengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// pagedServer serves /2/campaign/get/ with total campaigns split into pages of
+// the requested size, counting requests.
+func pagedServer(t *testing.T, total int, calls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		if size != maxPageSize {
+			t.Errorf("page_size = %d, want %d", size, maxPageSize)
+		}
+		totalPage := (total + size - 1) / size
+		list := "["
+		for i := (page-1)*size + 1; i <= min(page*size, total); i++ {
+			if i > (page-1)*size+1 {
+				list += ","
+			}
+			list += fmt.Sprintf(`{"id":%d}`, i)
+		}
+		list += "]"
+		fmt.Fprintf(w, `{"code":0,"data":{"list":%s,"page_info":{"page":%d,"page_size":%d,"total_number":%d,"total_page":%d}}}`,
+			list, page, size, total, totalPage)
+	}))
+}
+
+func TestAllCampaignsWalksEveryPage(t *testing.T) {
+	var calls int32
+	ts := pagedServer(t, 250, &calls)
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	var ids []int64
+	for camp, err := range c.AllCampaigns(context.Background(), 1, nil, 0) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, camp.ID)
+	}
+	if len(ids) != 250 || ids[249] != 250 || calls != 3 {
+		t.Fatalf("got %d campaigns in %d calls", len(ids), calls)
+	}
+}
+
+func TestAllCampaignsMaxItems(t *testing.T) {
+	var calls int32
+	ts := pagedServer(t, 250, &calls)
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL))
+	n := 0
+	for _, err := range c.AllCampaigns(context.Background(), 1, nil, 120) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 120 || calls != 2 {
+		t.Fatalf("got %d campaigns in %d calls, want 120 in 2", n, calls)
+	}
+}
+
+func TestAllCampaignsStopsOnBreakAndCancel(t *testing.T) {
+	var calls int32
+	ts := pagedServer(t, 250, &calls)
+	defer ts.Close()
+	c := NewClient("tok", WithBaseURL(ts.URL))
+
+	for range c.AllCampaigns(context.Background(), 1, nil, 0) {
+		break
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d after break, want 1", calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var gotErr error
+	n := 0
+	for _, err := range c.AllCampaigns(ctx, 1, nil, 0) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		if n++; n == 100 {
+			cancel()
+		}
+	}
+	if !errors.Is(gotErr, context.Canceled) || n != 100 {
+		t.Fatalf("after cancel: n = %d, err = %v", n, gotErr)
+	}
+}
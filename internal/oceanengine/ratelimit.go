@@ -0,0 +1,174 @@
+package oceanengine
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a RateLimiter. A zero QPS leaves that level unlimited;
+// a zero burst defaults to the QPS rounded up.
+type RateLimit struct {
+	// QPS and Burst cap every request made through the limiter, matching the
+	// per-app quota Ocean Engine enforces.
+	QPS   float64
+	Burst int
+	// AdvertiserQPS and AdvertiserBurst cap requests per (endpoint path,
+	// advertiser_id) pair, matching the per-advertiser endpoint quotas.
+	AdvertiserQPS   float64
+	AdvertiserBurst int
+}
+
+// RateLimiter is a client-side token-bucket limiter for Ocean Engine requests.
+// It is safe for concurrent use and may be shared by several Clients that draw
+// on the same app quota.
+type RateLimiter struct {
+	cfg RateLimit
+	app *bucket // nil when unlimited
+
+	mu      sync.Mutex
+	keyed   map[limitKey]*bucket
+	sweepAt int // sweep idle keyed buckets once there are this many
+}
+
+// minSweep is the fewest keyed buckets a RateLimiter sweeps for idle ones.
+const minSweep = 1024
+
+type limitKey struct {
+	path         string
+	advertiserID int64
+}
+
+// NewRateLimiter builds a RateLimiter from cfg.
+func NewRateLimiter(cfg RateLimit) *RateLimiter {
+	l := &RateLimiter{cfg: cfg, keyed: map[limitKey]*bucket{}, sweepAt: minSweep}
+	if cfg.QPS > 0 {
+		l.app = newBucket(cfg.QPS, cfg.Burst)
+	}
+	return l
+}
+
+// WithRateLimit makes the Client wait on l before every request attempt,
+// including retries.
+func WithRateLimit(l *RateLimiter) Option {
+	return func(c *Client) { c.limiter = l }
+}
+
+// Wait blocks until a request to path on behalf of advertiserID may proceed,
+// or returns the context's error if it is done first. Requests that carry no
+// advertiser (advertiserID 0) are only subject to the app-wide limit.
+func (l *RateLimiter) Wait(ctx context.Context, path string, advertiserID int64) error {
+	var keyed *bucket
+	if l.cfg.AdvertiserQPS > 0 && advertiserID != 0 {
+		var d time.Duration
+		keyed, d = l.reserveKeyed(limitKey{path, advertiserID})
+		if err := keyed.await(ctx, d); err != nil {
+			return err
+		}
+	}
+	if l.app != nil {
+		if err := l.app.wait(ctx); err != nil {
+			// The request will not be made; give its advertiser token back.
+			if keyed != nil {
+				keyed.release()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// reserveKeyed reserves a token from k's bucket, creating it if needed. The
+// reservation is made under l.mu so that a sweep cannot drop the bucket
+// between lookup and reservation.
+func (l *RateLimiter) reserveKeyed(k limitKey) (*bucket, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	b, ok := l.keyed[k]
+	if !ok {
+		if len(l.keyed) >= l.sweepAt {
+			l.sweepLocked(now)
+		}
+		b = newBucket(l.cfg.AdvertiserQPS, l.cfg.AdvertiserBurst)
+		l.keyed[k] = b
+	}
+	return b, b.reserve(now)
+}
+
+// sweepLocked drops the keyed buckets that have refilled to their burst: they
+// behave exactly like new ones, so one advertiser after another does not grow
+// the map without bound. It runs once the map has doubled since the last
+// sweep, which spreads its cost over the insertions.
+func (l *RateLimiter) sweepLocked(now time.Time) {
+	for k, b := range l.keyed {
+		if b.full(now) {
+			delete(l.keyed, k)
+		}
+	}
+	l.sweepAt = max(minSweep, 2*len(l.keyed))
+}
+
+// bucket is a single token bucket. Callers reserve a token up front and then
+// sleep until it becomes valid, so waiters are served in arrival order.
+type bucket struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(qps float64, burst int) *bucket {
+	if burst <= 0 {
+		burst = int(math.Ceil(qps))
+	}
+	return &bucket{rate: qps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token and returns how long the caller must wait before the
+// token is valid. The balance may go negative, queueing later callers.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// full reports whether the bucket will have refilled to its burst by now.
+func (b *bucket) full(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+}
+
+// release returns a reserved token that will not be used.
+func (b *bucket) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+func (b *bucket) wait(ctx context.Context) error {
+	return b.await(ctx, b.reserve(time.Now()))
+}
+
+// await sleeps for the delay d of a reserved token, giving the token back if
+// ctx is done first.
+func (b *bucket) await(ctx context.Context, d time.Duration) error {
+	if d == 0 {
+		return nil
+	}
+	if err := sleepCtx(ctx, d); err != nil {
+		b.release()
+		return err
+	}
+	return nil
+}
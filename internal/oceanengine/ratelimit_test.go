@@ -0,0 +1,164 @@
+package oceanengine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBucketBurstThenThrottle(t *testing.T) {
+	b := newBucket(10, 2)
+	now := b.last
+	if d := b.reserve(now); d != 0 {
+		t.Fatalf("first reserve waited %v", d)
+	}
+	if d := b.reserve(now); d != 0 {
+		t.Fatalf("second reserve waited %v", d)
+	}
+	if d := b.reserve(now); d != 100*time.Millisecond {
+		t.Fatalf("third reserve waited %v, want 100ms", d)
+	}
+	// After a full second the bucket is back at burst, not above it.
+	now = now.Add(time.Second)
+	b.reserve(now)
+	b.reserve(now)
+	if d := b.reserve(now); d == 0 {
+		t.Fatal("bucket refilled beyond its burst")
+	}
+}
+
+func TestRateLimiterKeysByAdvertiserAndPath(t *testing.T) {
+	l := NewRateLimiter(RateLimit{AdvertiserQPS: 1, AdvertiserBurst: 1})
+	ctx := context.Background()
+
+	// Distinct (path, advertiser) pairs each get their own burst.
+	for _, k := range []limitKey{{"/a", 1}, {"/a", 2}, {"/b", 1}} {
+		start := time.Now()
+		if err := l.Wait(ctx, k.path, k.advertiserID); err != nil {
+			t.Fatal(err)
+		}
+		if time.Since(start) > 50*time.Millisecond {
+			t.Fatalf("%+v waited on another key's bucket", k)
+		}
+	}
+
+	// A second request on an exhausted key blocks until the context gives up.
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "/a", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait = %v, want deadline exceeded", err)
+	}
+}
+
+func TestRateLimiterReleasesAdvertiserTokenOnAppTimeout(t *testing.T) {
+	l := NewRateLimiter(RateLimit{QPS: 1, Burst: 1, AdvertiserQPS: 1, AdvertiserBurst: 1})
+	// Exhaust the app-wide bucket with a request for another advertiser.
+	if err := l.Wait(context.Background(), "/a", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "/a", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait = %v, want deadline exceeded", err)
+	}
+	// Advertiser 1 made no request, so its own bucket must still be full.
+	if _, d := l.reserveKeyed(limitKey{"/a", 1}); d != 0 {
+		t.Fatalf("advertiser token was not returned; next request waits %v", d)
+	}
+}
+
+func TestRateLimiterEvictsIdleAdvertisers(t *testing.T) {
+	l := NewRateLimiter(RateLimit{AdvertiserQPS: 1, AdvertiserBurst: 1})
+	for id := range int64(minSweep) {
+		if err := l.Wait(context.Background(), "/a", id+1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Advertiser 1 has a request queued; everyone else will have refilled.
+	busy, _ := l.reserveKeyed(limitKey{"/a", 1})
+	l.mu.Lock()
+	l.sweepLocked(time.Now().Add(1500 * time.Millisecond))
+	n, kept := len(l.keyed), l.keyed[limitKey{"/a", 1}]
+	l.mu.Unlock()
+	if n != 1 || kept != busy {
+		t.Fatalf("%d buckets left after the sweep, want only the busy one", n)
+	}
+
+	// Idle buckets are swept as new advertisers arrive, so the map stays
+	// bounded.
+	l = NewRateLimiter(RateLimit{AdvertiserQPS: 1e6})
+	for id := range int64(10 * minSweep) {
+		if err := l.Wait(context.Background(), "/a", id+1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(l.keyed); n > 2*minSweep {
+		t.Fatalf("%d keyed buckets, want at most %d", n, 2*minSweep)
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	l := NewRateLimiter(RateLimit{QPS: 100, Burst: 1})
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background(), "/x", 0); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	// 1 immediate + 9 spaced 10ms apart.
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("10 requests at 100 QPS finished in %v", elapsed)
+	}
+}
+
+func TestClientWaitsOnRateLimiter(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[]}}`))
+	}))
+	defer ts.Close()
+
+	l := NewRateLimiter(RateLimit{AdvertiserQPS: 0.001, AdvertiserBurst: 1})
+	c := NewClient("tok", WithBaseURL(ts.URL), WithRateLimit(l))
+	if _, err := c.ListAds(context.Background(), 42, nil, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListAds(ctx, 42, nil, 1, 10); err == nil {
+		t.Fatal("expected the second request to be held back by the limiter")
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1; throttled request must not reach the API", calls)
+	}
+	// A different advertiser has its own quota.
+	if _, err := c.ListAds(context.Background(), 43, nil, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdvertiserIDOf(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "http://x/p?advertiser_id=7", nil)
+	multi, _ := http.NewRequest(http.MethodGet, `http://x/p?advertiser_ids=%5B8%2C9%5D`, nil)
+	post, _ := http.NewRequest(http.MethodPost, "http://x/p", strings.NewReader(`{"advertiser_id":10,"x":1}`))
+	none, _ := http.NewRequest(http.MethodGet, "http://x/p", nil)
+	for req, want := range map[*http.Request]int64{get: 7, multi: 8, post: 10, none: 0} {
+		if got := advertiserIDOf(req); got != want {
+			t.Errorf("advertiserIDOf(%s %s) = %d, want %d", req.Method, req.URL, got, want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package oceanengine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// AdvertiserTokenProvider is optionally implemented by a TokenProvider that
+// holds different tokens for different advertisers. The Client calls TokenFor
+// with the advertiser_id of each request instead of Token.
+type AdvertiserTokenProvider interface {
+	TokenProvider
+	TokenFor(ctx context.Context, advertiserID int64) (string, error)
+}
+
+// Account is one authorizing user's token and the advertisers it may act on.
+// For agency and majordomo (账号管家) setups, AdvertiserIDs includes the child
+// accounts the user manages.
+type Account struct {
+	// User identifies the authorizing user, e.g. a label or their Ocean
+	// Engine user ID. It must be unique within a registry.
+	User          string
+	AdvertiserIDs []int64
+	Tokens        TokenProvider
+}
+
+// TokenRegistry is an AdvertiserTokenProvider for agency setups where many
+// advertisers are authorized under different users, each with its own token
+// pair. It is safe for concurrent use.
+type TokenRegistry struct {
+	mu           sync.RWMutex
+	accounts     []*Account
+	byUser       map[string]*Account
+	byAdvertiser map[int64]*Account
+}
+
+// NewTokenRegistry returns an empty registry.
+func NewTokenRegistry() *TokenRegistry {
+	return &TokenRegistry{
+		byUser:       map[string]*Account{},
+		byAdvertiser: map[int64]*Account{},
+	}
+}
+
+// Register adds an account. It fails if the user is already registered or one
+// of the advertisers is already mapped to another user.
+func (r *TokenRegistry) Register(a Account) error {
+	if a.Tokens == nil {
+		return fmt.Errorf("oceanengine: account %q has no token provider", a.User)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byUser[a.User]; ok {
+		return fmt.Errorf("oceanengine: account %q already registered", a.User)
+	}
+	for _, id := range a.AdvertiserIDs {
+		if other, ok := r.byAdvertiser[id]; ok {
+			return fmt.Errorf("oceanengine: advertiser %d already registered to account %q", id, other.User)
+		}
+	}
+	acct := &a
+	acct.AdvertiserIDs = append([]int64(nil), a.AdvertiserIDs...)
+	r.accounts = append(r.accounts, acct)
+	r.byUser[a.User] = acct
+	for _, id := range a.AdvertiserIDs {
+		r.byAdvertiser[id] = acct
+	}
+	return nil
+}
+
+// AddAdvertisers maps further advertisers (for example newly discovered child
+// accounts) to an already registered user. Advertisers mapped to another user
+// are left alone.
+func (r *TokenRegistry) AddAdvertisers(user string, advertiserIDs ...int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	acct, ok := r.byUser[user]
+	if !ok {
+		return fmt.Errorf("oceanengine: unknown account %q", user)
+	}
+	for _, id := range advertiserIDs {
+		if _, taken := r.byAdvertiser[id]; !taken {
+			r.byAdvertiser[id] = acct
+			acct.AdvertiserIDs = append(acct.AdvertiserIDs, id)
+		}
+	}
+	return nil
+}
+
+// AddChildAdvertisers maps the child accounts of a majordomo or agency
+// advertiser to the account that owns parentID. It does nothing if parentID is
+// not registered.
+func (r *TokenRegistry) AddChildAdvertisers(parentID int64, childIDs ...int64) {
+	r.mu.RLock()
+	acct, ok := r.byAdvertiser[parentID]
+	r.mu.RUnlock()
+	if ok {
+		_ = r.AddAdvertisers(acct.User, childIDs...)
+	}
+}
+
+// Accounts returns a snapshot of the registered accounts, in registration
+// order.
+func (r *TokenRegistry) Accounts() []Account {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Account, len(r.accounts))
+	for i, a := range r.accounts {
+		out[i] = *a
+		out[i].AdvertiserIDs = append([]int64(nil), a.AdvertiserIDs...)
+	}
+	return out
+}
+
+// TokenFor implements AdvertiserTokenProvider. Requests for an advertiser no
+// account is authorized for fail with ErrPermissionDenied; requests that name
+// no advertiser (advertiserID 0) use Token.
+func (r *TokenRegistry) TokenFor(ctx context.Context, advertiserID int64) (string, error) {
+	if advertiserID == 0 {
+		return r.Token(ctx)
+	}
+	r.mu.RLock()
+	acct, ok := r.byAdvertiser[advertiserID]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: no registered account is authorized for advertiser %d", ErrPermissionDenied, advertiserID)
+	}
+	return acct.Tokens.Token(ctx)
+}
+
+// Token implements TokenProvider for requests that are not scoped to an
+// advertiser. It uses the first registered account.
+func (r *TokenRegistry) Token(ctx context.Context) (string, error) {
+	r.mu.RLock()
+	var tp TokenProvider
+	if len(r.accounts) > 0 {
+		tp = r.accounts[0].Tokens
+	}
+	r.mu.RUnlock()
+	if tp == nil {
+		return "", fmt.Errorf("oceanengine: token registry has no accounts")
+	}
+	return tp.Token(ctx)
+}
+
+// Invalidate implements TokenInvalidator by forwarding to every account's
+// provider; each ignores tokens it does not hold.
+func (r *TokenRegistry) Invalidate(token string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, a := range r.accounts {
+		if inv, ok := a.Tokens.(TokenInvalidator); ok {
+			inv.Invalidate(token)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package oceanengine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func testRegistry(t *testing.T) *TokenRegistry {
+	t.Helper()
+	r := NewTokenRegistry()
+	if err := r.Register(Account{User: "alice", AdvertiserIDs: []int64{1, 2}, Tokens: StaticToken("tok-alice")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(Account{User: "bob", AdvertiserIDs: []int64{3}, Tokens: StaticToken("tok-bob")}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRegistryRoutesByAdvertiser(t *testing.T) {
+	r := testRegistry(t)
+	ctx := context.Background()
+	for id, want := range map[int64]string{1: "tok-alice", 2: "tok-alice", 3: "tok-bob", 0: "tok-alice"} {
+		got, err := r.TokenFor(ctx, id)
+		if err != nil || got != want {
+			t.Errorf("TokenFor(%d) = %q, %v; want %q", id, got, err, want)
+		}
+	}
+	if _, err := r.TokenFor(ctx, 99); !IsPermissionDenied(err) {
+		t.Fatalf("TokenFor(unknown) = %v, want permission denied", err)
+	}
+}
+
+func TestRegistryRejectsConflicts(t *testing.T) {
+	r := testRegistry(t)
+	if err := r.Register(Account{User: "alice", Tokens: StaticToken("x")}); err == nil {
+		t.Error("duplicate user should be rejected")
+	}
+	if err := r.Register(Account{User: "carol", AdvertiserIDs: []int64{3}, Tokens: StaticToken("x")}); err == nil {
+		t.Error("advertiser owned by another account should be rejected")
+	}
+	if err := r.AddAdvertisers("bob", 4, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.TokenFor(context.Background(), 4); got != "tok-bob" {
+		t.Errorf("advertiser 4 → %q, want tok-bob", got)
+	}
+	if got, _ := r.TokenFor(context.Background(), 1); got != "tok-alice" {
+		t.Errorf("AddAdvertisers must not steal advertiser 1; got %q", got)
+	}
+}
+
+func TestClientUsesRegistryPerRequest(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]string{} // path → Access-Token
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = r.Header.Get("Access-Token")
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[]}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("", WithBaseURL(ts.URL), WithTokenProvider(testRegistry(t)))
+	ctx := context.Background()
+	if _, err := c.ListCampaigns(ctx, 3, nil, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateCampaignStatus(ctx, 2, []int64{9}, "disable"); err != nil {
+		t.Fatal(err)
+	}
+	if seen["/open_api/2/campaign/get/"] != "tok-bob" {
+		t.Errorf("GET for advertiser 3 used %q, want tok-bob", seen["/open_api/2/campaign/get/"])
+	}
+	if seen["/open_api/2/campaign/update/status/"] != "tok-alice" {
+		t.Errorf("POST for advertiser 2 used %q, want tok-alice", seen["/open_api/2/campaign/update/status/"])
+	}
+}
@@ -0,0 +1,143 @@
+package oceanengine
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// RetryPolicy controls how a Client retries failed requests. Retries back off
+// exponentially with jitter and never outlive the request's context.
+//
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values <= 1 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles on every
+	// further attempt, capped at MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// RetryableCodes are the envelope codes worth retrying, typically QPS
+	// limits and transient system errors.
+	RetryableCodes []int
+	// RetryableStatuses are the HTTP statuses worth retrying when the response
+	// carries no Ocean Engine envelope (e.g. a 502 from a gateway).
+	RetryableStatuses []int
+	// RetryWrites allows retrying POST requests. Ocean Engine write endpoints
+	// are not idempotent, so a retried write may be applied twice; leave this
+	// off unless every write the client makes is safe to repeat.
+	RetryWrites bool
+}
+
+// DefaultRetryPolicy returns the policy a Client uses unless WithRetryPolicy is
+// given: three attempts starting at 500ms, retrying reads on QPS-limit and
+// system-busy codes and on 429/5xx responses.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		RetryableCodes: []int{
+			CodeRateLimited,
+			CodeServiceRateLimited,
+			CodeSystemError,
+		},
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy replaces the Client's retry policy. Pass RetryPolicy{} to
+// disable retries.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// retryable reports whether err, returned by the given attempt of req, should
+// be retried under the policy.
+func (p RetryPolicy) retryable(req *http.Request, err error, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if req.Method != http.MethodGet && !p.RetryWrites {
+		return false
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return slices.Contains(p.RetryableCodes, apiErr.Code)
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return slices.Contains(p.RetryableStatuses, httpErr.StatusCode)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	// Only network failures (connection reset, timeout) are worth another
+	// try. Token provider and decode errors are local and would fail again.
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// backoff returns the delay before the retry that follows the given attempt:
+// BaseDelay doubled per attempt, capped at MaxDelay, with "equal jitter"
+// (half fixed, half random) so concurrent clients spread out.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// sleepCtx waits for d or until ctx is done. It gives up immediately if ctx's
+// deadline would pass before d elapses, since the caller could not proceed in
+// time anyway.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return context.DeadlineExceeded
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rewind returns a copy of req that can be sent again, with a fresh body.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
@@ -0,0 +1,199 @@
+package oceanengine
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetry is DefaultRetryPolicy with delays short enough for tests.
+func fastRetry() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	p.MaxDelay = 2 * time.Millisecond
+	return p
+}
+
+func TestRetryOnRateLimit(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			_, _ = w.Write([]byte(`{"code":40100,"message":"too frequent"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"list":[{"id":1}]}}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL), WithRetryPolicy(fastRetry()))
+	res, err := c.ListCampaigns(context.Background(), 1, nil, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || len(res.List) != 1 {
+		t.Fatalf("calls = %d, list = %+v", calls, res.List)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("<html>bad gateway</html>"))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL), WithRetryPolicy(fastRetry()))
+	_, err := c.ListAds(context.Background(), 1, nil, 1, 10)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected *HTTPError 502, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+}
+
+func TestNoRetryOnPermanentError(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"code":40001,"message":"bad param"}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL), WithRetryPolicy(fastRetry()))
+	if _, err := c.ListAds(context.Background(), 1, nil, 1, 10); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+// countingToken is a TokenProvider that always fails, counting its calls.
+type countingToken struct{ calls atomic.Int32 }
+
+func (c *countingToken) Token(context.Context) (string, error) {
+	c.calls.Add(1)
+	return "", errors.New("no refresh token available")
+}
+
+func TestNoRetryOnTokenError(t *testing.T) {
+	tokens := &countingToken{}
+	c := NewClient("", WithTokenProvider(tokens), WithRetryPolicy(fastRetry()))
+	if _, err := c.ListAds(context.Background(), 1, nil, 1, 10); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := tokens.calls.Load(); n != 1 {
+		t.Fatalf("attempts = %d, want 1", n)
+	}
+}
+
+// failingTransport refuses every request, counting them.
+type failingTransport struct{ calls atomic.Int32 }
+
+func (f *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	f.calls.Add(1)
+	return nil, errors.New("connection reset by peer")
+}
+
+func TestRetryOnNetworkError(t *testing.T) {
+	rt := &failingTransport{}
+	c := NewClient("tok", WithHTTPClient(&http.Client{Transport: rt}), WithRetryPolicy(fastRetry()))
+	_, err := c.ListAds(context.Background(), 1, nil, 1, 10)
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Fatalf("expected a *url.Error, got %v", err)
+	}
+	if n := rt.calls.Load(); n != 3 {
+		t.Fatalf("calls = %d, want 3", n)
+	}
+}
+
+func TestNoRetryOnWritesByDefault(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"code":40100,"message":"too frequent"}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient("tok", WithBaseURL(ts.URL), WithRetryPolicy(fastRetry()))
+	if _, err := c.UpdateCampaignStatus(context.Background(), 1, []int64{2}, "disable"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetryWritesResendsBody(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) == 0 {
+			t.Error("attempt sent an empty body")
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			_, _ = w.Write([]byte(`{"code":40100,"message":"too frequent"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0}`))
+	}))
+	defer ts.Close()
+
+	p := fastRetry()
+	p.RetryWrites = true
+	c := NewClient("tok", WithBaseURL(ts.URL), WithRetryPolicy(p))
+	if _, err := c.UpdateCampaignStatus(context.Background(), 1, []int64{2}, "disable"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2", calls)
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"code":40100,"message":"too frequent"}`))
+	}))
+	defer ts.Close()
+
+	p := DefaultRetryPolicy()
+	p.BaseDelay = time.Hour
+	c := NewClient("tok", WithBaseURL(ts.URL), WithRetryPolicy(p))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := c.ListAds(ctx, 1, nil, 1, 10)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 40100 {
+		t.Fatalf("expected the last API error, got %v", err)
+	}
+	if calls != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("calls = %d after %v; backoff should not outlive the deadline", calls, time.Since(start))
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, limit := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 5: time.Second} {
+		for range 20 {
+			d := p.backoff(attempt)
+			if d < limit/2 || d > limit {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", attempt, d, limit/2, limit)
+			}
+		}
+	}
+}
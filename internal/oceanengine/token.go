@@ -4,12 +4,32 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
 	"time"
 )
 
-// oauthRefreshPath is the Ocean Engine OAuth2 refresh endpoint.
-const oauthRefreshPath = "/open_api/oauth2/refresh_token/"
+// Ocean Engine OAuth2 endpoints.
+const (
+	oauthRefreshPath     = "/open_api/oauth2/refresh_token/"
+	oauthAccessTokenPath = "/open_api/oauth2/access_token/"
+)
+
+// AuthorizePageURL is the open-platform page where a user grants an app access
+// to their advertiser accounts.
+const AuthorizePageURL = "https://open.oceanengine.com/audit/oauth.html"
+
+// AuthorizeURL returns the URL a user opens to authorize appID. After consent
+// the browser is redirected to redirectURI (which must match the one
+// registered for the app) with auth_code and state query parameters.
+func AuthorizeURL(appID uint64, redirectURI, state string) string {
+	q := url.Values{}
+	q.Set("app_id", strconv.FormatUint(appID, 10))
+	q.Set("state", state)
+	q.Set("redirect_uri", redirectURI)
+	return AuthorizePageURL + "?" + q.Encode()
+}
 
 // TokenProvider supplies a valid access token for API requests, refreshing it
 // transparently when necessary. Implementations must be safe for concurrent use.
@@ -17,6 +37,16 @@ type TokenProvider interface {
 	Token(ctx context.Context) (string, error)
 }
 
+// TokenInvalidator is optionally implemented by a TokenProvider that can drop
+// a token the API has rejected before its expiry (for example after the user
+// re-authorized elsewhere), so that the next Token call obtains a new one.
+type TokenInvalidator interface {
+	// Invalidate discards token. Implementations ignore tokens they have
+	// already replaced, so concurrent requests rejected with the same token
+	// cause a single refresh.
+	Invalidate(token string)
+}
+
 // StaticToken is a TokenProvider that always returns the same token. Use it
 // when the access token's lifecycle is managed outside this package.
 type StaticToken string
@@ -37,12 +67,23 @@ type refreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// authCodeRequest is the body of the oauth2/access_token endpoint.
+type authCodeRequest struct {
+	AppID     uint64 `json:"app_id"`
+	Secret    string `json:"secret"`
+	GrantType string `json:"grant_type"`
+	AuthCode  string `json:"auth_code"`
+}
+
 // tokenData is the data payload returned by the OAuth token endpoints.
 type tokenData struct {
 	AccessToken           string `json:"access_token"`
 	RefreshToken          string `json:"refresh_token"`
 	ExpiresIn             int64  `json:"expires_in"`               // seconds
 	RefreshTokenExpiresIn int64  `json:"refresh_token_expires_in"` // seconds
+	// AdvertiserIDs is only returned by oauth2/access_token: the accounts the
+	// user authorized.
+	AdvertiserIDs []int64 `json:"advertiser_ids"`
 }
 
 // RefreshingTokenSource holds an Ocean Engine OAuth token pair and refreshes the
@@ -50,7 +91,8 @@ type tokenData struct {
 //
 // Ocean Engine rotates the refresh token on every refresh (the previous one is
 // invalidated), so callers that need to persist the refresh token across process
-// restarts should register an OnRefresh callback and store the latest value.
+// restarts should configure a TokenStore (WithTokenStore) or register an
+// OnRefresh callback and store the latest value.
 //
 // It is safe for concurrent use.
 type RefreshingTokenSource struct {
@@ -62,12 +104,15 @@ type RefreshingTokenSource struct {
 	skew       time.Duration // refresh this long before expiry
 	now        func() time.Time
 	onRefresh  func(accessToken, refreshToken string, accessExpiry, refreshExpiry time.Time)
+	store      TokenStore
+	onSaveErr  func(error)
 
 	mu            sync.Mutex
 	accessToken   string
 	refreshToken  string
 	accessExpiry  time.Time
 	refreshExpiry time.Time
+	stored        Token // the pair last read from or written to store
 }
 
 // RefreshOption customizes a RefreshingTokenSource.
@@ -108,6 +153,22 @@ func WithOnRefresh(fn func(accessToken, refreshToken string, accessExpiry, refre
 	return func(s *RefreshingTokenSource) { s.onRefresh = fn }
 }
 
+// WithTokenStore persists every refreshed token pair to store. Before each
+// refresh the source reloads the store, adopting a pair another process has
+// already rotated; if store is a TokenLocker the reload, refresh and save run
+// under its lock. A failed save does not fail the refresh, since the new pair
+// is valid; see WithOnSaveError.
+func WithTokenStore(store TokenStore) RefreshOption {
+	return func(s *RefreshingTokenSource) { s.store = store }
+}
+
+// WithOnSaveError registers a callback invoked when a refreshed token pair
+// could not be saved to the TokenStore. The pair stays in use, but will be
+// lost on restart: the stored refresh token has been rotated out.
+func WithOnSaveError(fn func(error)) RefreshOption {
+	return func(s *RefreshingTokenSource) { s.onSaveErr = fn }
+}
+
 // NewRefreshingTokenSource builds a self-refreshing token source from an OAuth
 // token pair. accessExpiresIn is the remaining lifetime of accessToken; pass 0
 // (or an empty accessToken) to force a refresh on first use.
@@ -146,7 +207,65 @@ func (s *RefreshingTokenSource) Token(ctx context.Context) (string, error) {
 	return s.accessToken, nil
 }
 
+// Invalidate implements TokenInvalidator, forcing a refresh on the next Token
+// call if token is still the current access token.
+func (s *RefreshingTokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token == s.accessToken {
+		s.accessExpiry = time.Time{}
+	}
+}
+
+// ExchangeAuthCode trades an auth_code from the authorization redirect (see
+// AuthorizeURL) for a new token pair, installs it and persists it to the
+// source's TokenStore. It returns the advertiser IDs the user authorized.
+//
+// POST /open_api/oauth2/access_token/
+func (s *RefreshingTokenSource) ExchangeAuthCode(ctx context.Context, authCode string) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.store.(TokenLocker); ok {
+		unlock, err := l.Lock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+	body := authCodeRequest{
+		AppID:     s.appID,
+		Secret:    s.secret,
+		GrantType: "auth_code",
+		AuthCode:  authCode,
+	}
+	var data tokenData
+	if err := postJSON(ctx, s.httpClient, s.baseURL+oauthAccessTokenPath, body, &data); err != nil {
+		return nil, fmt.Errorf("oceanengine: exchange auth code: %w", err)
+	}
+	if err := s.setLocked(data); err != nil {
+		return nil, err
+	}
+	if err := s.saveLocked(ctx); err != nil {
+		return nil, err
+	}
+	return data.AdvertiserIDs, nil
+}
+
 func (s *RefreshingTokenSource) refreshLocked(ctx context.Context) error {
+	if s.store != nil {
+		if l, ok := s.store.(TokenLocker); ok {
+			unlock, err := l.Lock(ctx)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+		}
+		adopted, err := s.reloadLocked(ctx)
+		if err != nil || adopted {
+			return err
+		}
+	}
 	if s.refreshToken == "" {
 		return fmt.Errorf("oceanengine: no refresh token available")
 	}
@@ -160,8 +279,48 @@ func (s *RefreshingTokenSource) refreshLocked(ctx context.Context) error {
 	if err := postJSON(ctx, s.httpClient, s.baseURL+oauthRefreshPath, body, &data); err != nil {
 		return fmt.Errorf("oceanengine: refresh token: %w", err)
 	}
+	if err := s.setLocked(data); err != nil {
+		return err
+	}
+	// The new pair is already in use; a failed save only matters after a
+	// restart, so it must not fail the request that needed the token.
+	if err := s.saveLocked(ctx); err != nil && s.onSaveErr != nil {
+		s.onSaveErr(err)
+	}
+	return nil
+}
+
+// reloadLocked reads the store and adopts a pair another process has rotated
+// since this source last saw it. It reports whether the stored access token is
+// fresh enough to use without refreshing.
+//
+// A pair this source already read or wrote is ignored: if saving a rotated
+// pair failed, the store still holds the pair it replaced, whose refresh token
+// is no longer valid.
+func (s *RefreshingTokenSource) reloadLocked(ctx context.Context) (bool, error) {
+	t, err := s.store.Load(ctx)
+	if err != nil || t == nil {
+		return false, err
+	}
+	if t.AccessToken == s.stored.AccessToken && t.RefreshToken == s.stored.RefreshToken {
+		return false, nil
+	}
+	s.stored = *t
+	if t.RefreshToken != "" {
+		s.refreshToken = t.RefreshToken // ours may already be rotated out
+	}
+	if t.AccessToken == "" || t.AccessToken == s.accessToken || !s.now().Before(t.AccessExpiry.Add(-s.skew)) {
+		return false, nil
+	}
+	s.accessToken, s.accessExpiry, s.refreshExpiry = t.AccessToken, t.AccessExpiry, t.RefreshExpiry
+	return true, nil
+}
+
+// setLocked installs a token pair returned by an OAuth endpoint and notifies
+// the OnRefresh callback.
+func (s *RefreshingTokenSource) setLocked(data tokenData) error {
 	if data.AccessToken == "" {
-		return fmt.Errorf("oceanengine: refresh returned empty access token")
+		return fmt.Errorf("oceanengine: token endpoint returned empty access token")
 	}
 
 	s.accessToken = data.AccessToken
@@ -178,3 +337,21 @@ func (s *RefreshingTokenSource) refreshLocked(ctx context.Context) error {
 	}
 	return nil
 }
+
+// saveLocked persists the current token pair to the store, if there is one.
+func (s *RefreshingTokenSource) saveLocked(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	t := Token{
+		AccessToken:   s.accessToken,
+		RefreshToken:  s.refreshToken,
+		AccessExpiry:  s.accessExpiry,
+		RefreshExpiry: s.refreshExpiry,
+	}
+	if err := s.store.Save(ctx, &t); err != nil {
+		return fmt.Errorf("oceanengine: persist refreshed token: %w", err)
+	}
+	s.stored = t
+	return nil
+}
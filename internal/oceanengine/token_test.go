@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -173,3 +175,147 @@ func TestClientUsesRefreshingSource(t *testing.T) {
 		t.Fatalf("expected 1 refresh, got %d", calls)
 	}
 }
+
+func TestReauthOnRejectedToken(t *testing.T) {
+	var calls int32
+	var bodies []refreshRequest
+	authTS := refreshServer(t, &calls, &bodies)
+	defer authTS.Close()
+
+	// The API revokes access-1 early; only access-2 is accepted.
+	var apiCalls int32
+	apiTS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&apiCalls, 1)
+		if r.Header.Get("Access-Token") != "access-2" {
+			_, _ = w.Write([]byte(`{"code":40105,"message":"access token invalid"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0}`))
+	}))
+	defer apiTS.Close()
+
+	src := NewRefreshingTokenSource(1, "s", "", "seed", 0, WithRefreshBaseURL(authTS.URL))
+	c := NewClient("", WithBaseURL(apiTS.URL), WithTokenProvider(src))
+
+	// Writes are replayed too: a rejected token means nothing was applied.
+	if _, err := c.UpdateCampaignStatus(context.Background(), 1, []int64{2}, "disable"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || apiCalls != 2 {
+		t.Fatalf("refreshes = %d, api calls = %d; want 2 and 2", calls, apiCalls)
+	}
+}
+
+func TestListAuthorizedAdvertisersReauths(t *testing.T) {
+	var calls int32
+	var bodies []refreshRequest
+	authTS := refreshServer(t, &calls, &bodies)
+	defer authTS.Close()
+
+	// The OAuth endpoint takes the token as a query parameter; it too gets a
+	// fresh token after a rejection, and its request_ids are collected.
+	apiTS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "access-2" {
+			_, _ = w.Write([]byte(`{"code":40105,"message":"access token invalid","request_id":"req-1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"request_id":"req-2","data":{"list":[{"advertiser_id":1}]}}`))
+	}))
+	defer apiTS.Close()
+
+	src := NewRefreshingTokenSource(1, "s", "", "seed", 0, WithRefreshBaseURL(authTS.URL))
+	c := NewClient("", WithBaseURL(apiTS.URL), WithTokenProvider(src), WithAppCredentials(1, "s"))
+	ctx, requestIDs := WithRequestIDs(context.Background())
+	res, err := c.ListAuthorizedAdvertisers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || calls != 2 {
+		t.Fatalf("res = %+v after %d refreshes", res, calls)
+	}
+	if ids := requestIDs(); len(ids) != 2 || ids[1] != "req-2" {
+		t.Fatalf("request IDs = %v", ids)
+	}
+}
+
+func TestReauthReplaysOnlyOnce(t *testing.T) {
+	var calls int32
+	var bodies []refreshRequest
+	authTS := refreshServer(t, &calls, &bodies)
+	defer authTS.Close()
+
+	var apiCalls int32
+	apiTS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&apiCalls, 1)
+		_, _ = w.Write([]byte(`{"code":40102,"message":"access token expired"}`))
+	}))
+	defer apiTS.Close()
+
+	src := NewRefreshingTokenSource(1, "s", "", "seed", 0, WithRefreshBaseURL(authTS.URL))
+	c := NewClient("", WithBaseURL(apiTS.URL), WithTokenProvider(src))
+
+	_, err := c.ListAds(context.Background(), 1, nil, 1, 10)
+	if !IsTokenExpired(err) {
+		t.Fatalf("expected token expired error, got %v", err)
+	}
+	if apiCalls != 2 {
+		t.Fatalf("api calls = %d, want 2 (original + one replay)", apiCalls)
+	}
+}
+
+func TestInvalidateIgnoresStaleToken(t *testing.T) {
+	src := NewRefreshingTokenSource(1, "s", "current", "r", time.Hour)
+	src.Invalidate("stale")
+	if src.accessExpiry.IsZero() {
+		t.Fatal("invalidating a replaced token must not force a refresh")
+	}
+	src.Invalidate("current")
+	if !src.accessExpiry.IsZero() {
+		t.Fatal("invalidating the current token must force a refresh")
+	}
+}
+
+func TestAuthorizeURL(t *testing.T) {
+	u, err := url.Parse(AuthorizeURL(42, "http://127.0.0.1:8765/callback", "xyz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("app_id") != "42" || q.Get("state") != "xyz" || q.Get("redirect_uri") != "http://127.0.0.1:8765/callback" {
+		t.Fatalf("unexpected authorize URL query: %v", q)
+	}
+}
+
+func TestExchangeAuthCode(t *testing.T) {
+	var got authCodeRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != oauthAccessTokenPath {
+			t.Errorf("path = %q, want %q", r.URL.Path, oauthAccessTokenPath)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"code":0,"data":{"access_token":"a","refresh_token":"r",
+			"expires_in":86400,"refresh_token_expires_in":2592000,"advertiser_ids":[7,8]}}`))
+	}))
+	defer ts.Close()
+
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token.json"))
+	src := NewRefreshingTokenSource(42, "sec", "", "", 0, WithRefreshBaseURL(ts.URL), WithTokenStore(store))
+	ids, err := src.ExchangeAuthCode(context.Background(), "code-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AppID != 42 || got.Secret != "sec" || got.GrantType != "auth_code" || got.AuthCode != "code-1" {
+		t.Fatalf("unexpected exchange body: %+v", got)
+	}
+	if len(ids) != 2 || ids[0] != 7 {
+		t.Fatalf("advertiser ids = %v", ids)
+	}
+	stored, err := store.Load(context.Background())
+	if err != nil || stored == nil || stored.RefreshToken != "r" {
+		t.Fatalf("stored = %+v, %v", stored, err)
+	}
+	// The exchanged token is served without another round trip.
+	if tok, err := src.Token(context.Background()); err != nil || tok != "a" {
+		t.Fatalf("Token() = %q, %v", tok, err)
+	}
+}
@@ -0,0 +1,108 @@
+package oceanengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Token is an OAuth token pair as persisted by a TokenStore.
+type Token struct {
+	AccessToken   string    `json:"access_token"`
+	RefreshToken  string    `json:"refresh_token"`
+	AccessExpiry  time.Time `json:"access_expiry"`
+	RefreshExpiry time.Time `json:"refresh_expiry,omitzero"`
+}
+
+// TokenStore persists the token pair of a RefreshingTokenSource, so that the
+// refresh token Ocean Engine rotates on every refresh survives restarts.
+// Implementations must be safe for concurrent use.
+type TokenStore interface {
+	// Load returns the stored token pair, or nil if nothing is stored yet.
+	Load(ctx context.Context) (*Token, error)
+	// Save replaces the stored token pair.
+	Save(ctx context.Context, t *Token) error
+}
+
+// TokenLocker is optionally implemented by a TokenStore shared between
+// processes. A RefreshingTokenSource holds the lock across reload, refresh and
+// save, so two processes never rotate the same refresh token: the second one
+// picks up the pair the first one stored instead.
+type TokenLocker interface {
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// FileTokenStore is a TokenStore backed by a JSON file. Writes are atomic
+// (temp file + rename) and the file is only readable by its owner. On Unix it
+// implements TokenLocker with an advisory lock on a sibling ".lock" file.
+type FileTokenStore struct {
+	path string
+}
+
+// NewFileTokenStore returns a store that keeps the token pair at path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Load implements TokenStore. A missing file is not an error.
+func (f *FileTokenStore) Load(context.Context) (*Token, error) {
+	raw, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oceanengine: read token file: %w", err)
+	}
+	var t Token
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("oceanengine: decode token file %s: %w", f.path, err)
+	}
+	return &t, nil
+}
+
+// Save implements TokenStore.
+func (f *FileTokenStore) Save(_ context.Context, t *Token) error {
+	raw, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("oceanengine: write token file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("oceanengine: write token file: %w", err)
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("oceanengine: write token file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("oceanengine: write token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("oceanengine: write token file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("oceanengine: write token file: %w", err)
+	}
+	return nil
+}
+
+// Lock implements TokenLocker. It blocks until the lock is acquired or ctx is
+// done.
+func (f *FileTokenStore) Lock(ctx context.Context) (func(), error) {
+	unlock, err := lockFile(ctx, f.path+".lock")
+	if err != nil {
+		return nil, fmt.Errorf("oceanengine: lock token file: %w", err)
+	}
+	return unlock, nil
+}
@@ -0,0 +1,161 @@
+package oceanengine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	store := NewFileTokenStore(path)
+	ctx := context.Background()
+
+	got, err := store.Load(ctx)
+	if err != nil || got != nil {
+		t.Fatalf("Load on missing file = %+v, %v; want nil, nil", got, err)
+	}
+
+	want := &Token{AccessToken: "a", RefreshToken: "r", AccessExpiry: time.Unix(1700000000, 0).UTC()}
+	if err := store.Save(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err = store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessToken != "a" || got.RefreshToken != "r" || !got.AccessExpiry.Equal(want.AccessExpiry) {
+		t.Fatalf("Load = %+v, want %+v", got, want)
+	}
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := fi.Mode().Perm(); perm != 0o600 {
+			t.Fatalf("token file mode = %o, want 600", perm)
+		}
+	}
+}
+
+func TestRefreshPersistsToStore(t *testing.T) {
+	var calls int32
+	var bodies []refreshRequest
+	ts := refreshServer(t, &calls, &bodies)
+	defer ts.Close()
+
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token.json"))
+	src := NewRefreshingTokenSource(1, "s", "", "seed", 0, WithRefreshBaseURL(ts.URL), WithTokenStore(store))
+	if _, err := src.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Load(context.Background())
+	if err != nil || got == nil {
+		t.Fatalf("Load = %+v, %v", got, err)
+	}
+	if got.AccessToken != "access-1" || got.RefreshToken != "refresh-1" {
+		t.Fatalf("stored %+v, want the rotated pair", got)
+	}
+}
+
+func TestSharedStoreRotatesOnce(t *testing.T) {
+	var calls int32
+	var bodies []refreshRequest
+	ts := refreshServer(t, &calls, &bodies)
+	defer ts.Close()
+
+	// Two "processes" seeded with the same refresh token share one file.
+	path := filepath.Join(t.TempDir(), "token.json")
+	a := NewRefreshingTokenSource(1, "s", "", "seed", 0, WithRefreshBaseURL(ts.URL), WithTokenStore(NewFileTokenStore(path)))
+	b := NewRefreshingTokenSource(1, "s", "", "seed", 0, WithRefreshBaseURL(ts.URL), WithTokenStore(NewFileTokenStore(path)))
+
+	ta, err := a.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb, err := b.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("refresh calls = %d, want 1; the second source should adopt the stored pair", calls)
+	}
+	if ta != tb || b.refreshToken != "refresh-1" {
+		t.Fatalf("b = %q/%q, want a's pair %q/refresh-1", tb, b.refreshToken, ta)
+	}
+
+	// Once the shared access token expires, b refreshes with the rotated
+	// refresh token rather than the stale seed.
+	b.Invalidate(tb)
+	if _, err := b.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || bodies[1].RefreshToken != "refresh-1" {
+		t.Fatalf("second refresh used %q after %d calls", bodies[len(bodies)-1].RefreshToken, calls)
+	}
+}
+
+// failingSaveStore is a TokenStore whose Save always fails.
+type failingSaveStore struct{ TokenStore }
+
+func (failingSaveStore) Save(context.Context, *Token) error { return errors.New("disk full") }
+
+func TestFailedSaveKeepsRotatedRefreshToken(t *testing.T) {
+	var calls int32
+	var bodies []refreshRequest
+	ts := refreshServer(t, &calls, &bodies)
+	defer ts.Close()
+
+	file := NewFileTokenStore(filepath.Join(t.TempDir(), "token.json"))
+	if err := file.Save(context.Background(), &Token{AccessToken: "access-0", RefreshToken: "seed"}); err != nil {
+		t.Fatal(err)
+	}
+	var saveErrs []error
+	src := NewRefreshingTokenSource(1, "s", "", "seed", 0, WithRefreshBaseURL(ts.URL), WithTokenStore(failingSaveStore{file}),
+		WithOnSaveError(func(err error) { saveErrs = append(saveErrs, err) }))
+	// The rotated pair is valid, so it is returned; the failed save is only
+	// reported.
+	if tok, err := src.Token(context.Background()); err != nil || tok != "access-1" {
+		t.Fatalf("Token() = %q, %v; want access-1", tok, err)
+	}
+	if len(saveErrs) != 1 {
+		t.Fatalf("save errors = %v, want one", saveErrs)
+	}
+
+	// The store still holds the rotated-out seed; the next refresh must use
+	// the pair the source got back instead.
+	src.Invalidate("access-1")
+	if _, err := src.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || bodies[1].RefreshToken != "refresh-1" {
+		t.Fatalf("second refresh used %q after %d calls, want refresh-1", bodies[len(bodies)-1].RefreshToken, calls)
+	}
+}
+
+func TestFileLockExcludes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("advisory locks are a no-op on this platform")
+	}
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token.json"))
+	unlock, err := store.Lock(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := store.Lock(ctx); err == nil {
+		t.Fatal("second Lock should block while the first is held")
+	}
+	unlock()
+	unlock2, err := store.Lock(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock2()
+}